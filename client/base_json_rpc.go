@@ -14,6 +14,9 @@ type DefaultPayload struct {
     Params *Params `json:"params"`
 }
 
+// StatusInProgress is returned in M3RawResult.Status while async action is still processed by server
+const StatusInProgress = "IN_PROGRESS"
+
 //M3RawResult contains fields that will be returned by server in a response for single request
 type M3RawResult struct {
     ID         string `json:"id"`
@@ -27,3 +30,8 @@ type M3RawResult struct {
 type M3BatchResult struct {
     Results []*M3RawResult `json:"results"`
 }
+
+//AsyncResultsRequest contains ids of async requests which results should be returned
+type AsyncResultsRequest struct {
+    RequestIds []string `json:"requestIds"`
+}
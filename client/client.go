@@ -1,5 +1,7 @@
 package client
 
+import "context"
+
 //go:generate go install github.com/golang/mock/mockgen@v1.6.0
 //go:generate mockgen -source ./client.go -destination ./mock/client_mock.go -package cmock
 
@@ -10,7 +12,6 @@ const (
     valueAccept           = "application/json"
     valueClientIdentifier = "terraform-provider"
     valueSdkVersion       = "3.2.80"
-    valueAsync            = "false"
 
     headerContentType      = "Content-type"
     headerAccept           = "Accept"
@@ -21,11 +22,16 @@ const (
     headerUserIdentifier   = "maestro-user-identifier"
     headerSdkVersion       = "maestro-sdk-version"
     headerAsync            = "maestro-sdk-async"
+
+    // resultsPath is appended to URL to poll results of async requests. The endpoint is not part of
+    // the documented Maestro3 API, so async mode is opt-in and is used only with servers which provide it
+    resultsPath = "/results"
 )
 
 // Transporter interface witch provide transports possibility
 type Transporter interface {
-    Do(body interface{}) (*M3BatchResult, error)
+    Do(ctx context.Context, body interface{}) (*M3BatchResult, error)
+    DoBatch(ctx context.Context, payloads []*DefaultPayload) (*M3BatchResult, error)
     MakePayload(interface{}, string) (*DefaultPayload, error)
 }
 
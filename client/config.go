@@ -13,7 +13,6 @@ import (
     "io"
     "log"
     "net/http"
-    "os"
     "strconv"
     "strings"
     "time"
@@ -30,66 +29,37 @@ type Config struct {
     TenantName     string `json:"tenant_name"`
     RegionName     string `json:"regionName"`
     Cloud          string `json:"cloud"`
+
+    Async AsyncConfig `json:"-"`
+    Retry RetryPolicy `json:"-"`
+    // SensitiveFields contains JSON fields which are masked in logs in addition to DefaultSensitiveFields
+    SensitiveFields []string `json:"-"`
 }
 
-func NewConfig(url, userIdentifier, accessKey, secretKey, tenantName, regionName, cloud string) *Config {
-    tr := &http.Transport{
-        TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-    }
+// AsyncConfig contains settings of async mode, in which server answers immediately and results are polled
+type AsyncConfig struct {
+    Enabled      bool
+    PollInterval time.Duration
+    Timeout      time.Duration
+}
 
-    if len(url) > 0 {
-        return &Config{
-            Client: &http.Client{
-                Transport: tr,
-                Timeout:   time.Minute * 2,
-            },
-            URL:            url,
-            UserIdentifier: userIdentifier,
-            AccessKey:      accessKey,
-            SecretKey:      secretKey,
-            TenantName:     tenantName,
-            RegionName:     regionName,
-            Cloud:          cloud,
-        }
-    }
-    url, exists := os.LookupEnv("TERRAFORM_M3_URL")
-    if !exists {
-        log.Fatalf("ENV VARIABLE 'TERRAFORM_M3_URL' IS NOT SET")
-    }
-    userIdentifier, exists = os.LookupEnv("TERRAFORM_M3_USER_IDENTIFIER")
-    if !exists {
-        log.Fatalf("ENV VARIABLE 'TERRAFORM_M3_USER_IDENTIFIER' IS NOT SET")
-    }
-    accessKey, exists = os.LookupEnv("TERRAFORM_M3_ACCESS_KEY")
-    if !exists {
-        log.Fatalf("ENV VARIABLE 'TERRAFORM_M3_ACCESS_KEY' IS NOT SET")
-    }
-    secretKey, exists = os.LookupEnv("TERRAFORM_M3_SECRET_KEY")
-    if !exists {
-        log.Fatalf("ENV VARIABLE 'TERRAFORM_M3_SECRET_KEY' IS NOT SET")
-    }
-    tenantName, exists = os.LookupEnv("TERRAFORM_M3_TENANT_NAME")
-    if !exists {
-        tenantName = ""
-    }
-    regionName, exists = os.LookupEnv("TERRAFORM_M3_REGION_NAME")
-    if !exists {
-        regionName = ""
-    }
-    cloud, exists = os.LookupEnv("TERRAFORM_M3_CLOUD")
-    if !exists {
-        cloud = ""
+func NewConfig(creds *Credentials, tlsConfig *tls.Config) *Config {
+    tr := &http.Transport{
+        TLSClientConfig: tlsConfig,
     }
 
     return &Config{
-        Client:         &http.Client{Transport: tr},
-        URL:            url,
-        UserIdentifier: userIdentifier,
-        AccessKey:      accessKey,
-        SecretKey:      secretKey,
-        TenantName:     tenantName,
-        RegionName:     regionName,
-        Cloud:          cloud,
+        Client: &http.Client{
+            Transport: tr,
+            Timeout:   time.Minute * 2,
+        },
+        URL:            creds.URL,
+        UserIdentifier: creds.UserIdentifier,
+        AccessKey:      creds.AccessKey,
+        SecretKey:      creds.SecretKey,
+        TenantName:     creds.TenantName,
+        RegionName:     creds.RegionName,
+        Cloud:          creds.Cloud,
     }
 }
 
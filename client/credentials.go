@@ -0,0 +1,272 @@
+package client
+
+import (
+    "bufio"
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strings"
+)
+
+// DefaultProfile is used when profile of shared credentials file is not specified
+const DefaultProfile = "default"
+
+// Credentials contains connection settings which are resolved from credential sources
+type Credentials struct {
+    URL            string `json:"url"`
+    AccessKey      string `json:"access_key"`
+    SecretKey      string `json:"secret_key"`
+    UserIdentifier string `json:"user_identifier"`
+    TenantName     string `json:"tenant"`
+    RegionName     string `json:"region"`
+    Cloud          string `json:"cloud"`
+}
+
+// CredentialSource provides credentials, values missing in the source are left empty
+type CredentialSource interface {
+    // Name describes source in diagnostics
+    Name() string
+    Retrieve(ctx context.Context) (*Credentials, error)
+}
+
+// CredentialChain resolves credentials from sources in order, every value is taken from the first source providing it.
+// Sources after the one completing required values are not read, access and secret keys are always taken from one source
+type CredentialChain []CredentialSource
+
+// Retrieve merges credentials of sources until required values are set
+func (c CredentialChain) Retrieve(ctx context.Context) (*Credentials, error) {
+    creds := &Credentials{}
+    names := make([]string, 0, len(c))
+    for _, source := range c {
+        if len(creds.missing()) == 0 {
+            break
+        }
+        names = append(names, source.Name())
+        sourceCreds, err := source.Retrieve(ctx)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", source.Name(), err)
+        }
+        if sourceCreds == nil {
+            continue
+        }
+        if err := creds.merge(sourceCreds); err != nil {
+            return nil, fmt.Errorf("%s: %w", source.Name(), err)
+        }
+    }
+
+    missing := creds.missing()
+    if len(missing) > 0 {
+        return nil, fmt.Errorf("%s are not set, checked sources: %s",
+            strings.Join(missing, ", "), strings.Join(names, ", "))
+    }
+    return creds, nil
+}
+
+// merge sets values which are still empty from other, access and secret keys are set only as a pair
+func (c *Credentials) merge(other *Credentials) error {
+    if (other.AccessKey == "") != (other.SecretKey == "") {
+        return errors.New("access_key and secret_key must be set together")
+    }
+    if c.AccessKey == "" {
+        c.AccessKey = other.AccessKey
+        c.SecretKey = other.SecretKey
+    }
+
+    fill := func(value *string, otherValue string) {
+        if *value == "" {
+            *value = otherValue
+        }
+    }
+    fill(&c.URL, other.URL)
+    fill(&c.UserIdentifier, other.UserIdentifier)
+    fill(&c.TenantName, other.TenantName)
+    fill(&c.RegionName, other.RegionName)
+    fill(&c.Cloud, other.Cloud)
+    return nil
+}
+
+// missing returns names of required values which are not set
+func (c *Credentials) missing() []string {
+    missing := make([]string, 0, 4)
+    if c.URL == "" {
+        missing = append(missing, "url")
+    }
+    if c.UserIdentifier == "" {
+        missing = append(missing, "user_identifier")
+    }
+    if c.AccessKey == "" {
+        missing = append(missing, "access_key")
+    }
+    if c.SecretKey == "" {
+        missing = append(missing, "secret_key")
+    }
+    return missing
+}
+
+// StaticCredentials are credentials set explicitly in provider arguments
+type StaticCredentials struct {
+    Credentials
+}
+
+func (s *StaticCredentials) Name() string {
+    return "provider arguments"
+}
+
+func (s *StaticCredentials) Retrieve(ctx context.Context) (*Credentials, error) {
+    creds := s.Credentials
+    return &creds, nil
+}
+
+// EnvCredentials reads credentials from TERRAFORM_M3_* environment variables
+type EnvCredentials struct{}
+
+func (e *EnvCredentials) Name() string {
+    return "environment variables"
+}
+
+func (e *EnvCredentials) Retrieve(ctx context.Context) (*Credentials, error) {
+    return &Credentials{
+        URL:            os.Getenv("TERRAFORM_M3_URL"),
+        AccessKey:      os.Getenv("TERRAFORM_M3_ACCESS_KEY"),
+        SecretKey:      os.Getenv("TERRAFORM_M3_SECRET_KEY"),
+        UserIdentifier: os.Getenv("TERRAFORM_M3_USER_IDENTIFIER"),
+        TenantName:     os.Getenv("TERRAFORM_M3_TENANT_NAME"),
+        RegionName:     os.Getenv("TERRAFORM_M3_REGION_NAME"),
+        Cloud:          os.Getenv("TERRAFORM_M3_CLOUD"),
+    }, nil
+}
+
+// SharedCredentialsFile reads credentials of a profile from INI file, ~/.m3/credentials by default:
+//
+//  [default]
+//  url = https://host/maestro/api/V3
+//  access_key = ...
+//  secret_key = ...
+//  user_identifier = ...
+type SharedCredentialsFile struct {
+    Path    string
+    Profile string
+}
+
+// DefaultSharedCredentialsFile returns path to the shared credentials file in user home directory
+func DefaultSharedCredentialsFile() string {
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return ""
+    }
+    return filepath.Join(home, ".m3", "credentials")
+}
+
+func (f *SharedCredentialsFile) Name() string {
+    return fmt.Sprintf("shared credentials file %s (profile %s)", f.path(), f.profile())
+}
+
+func (f *SharedCredentialsFile) Retrieve(ctx context.Context) (*Credentials, error) {
+    content, err := os.ReadFile(f.path())
+    if err != nil {
+        // default file is optional unless a profile is requested from it
+        if errors.Is(err, os.ErrNotExist) && f.Path == "" && f.Profile == "" {
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    profiles, err := parseINI(content)
+    if err != nil {
+        return nil, err
+    }
+    values, ok := profiles[f.profile()]
+    if !ok {
+        return nil, fmt.Errorf("profile '%s' is not found", f.profile())
+    }
+    return &Credentials{
+        URL:            values["url"],
+        AccessKey:      values["access_key"],
+        SecretKey:      values["secret_key"],
+        UserIdentifier: values["user_identifier"],
+        TenantName:     values["tenant"],
+        RegionName:     values["region"],
+        Cloud:          values["cloud"],
+    }, nil
+}
+
+func (f *SharedCredentialsFile) path() string {
+    if f.Path != "" {
+        return f.Path
+    }
+    return DefaultSharedCredentialsFile()
+}
+
+func (f *SharedCredentialsFile) profile() string {
+    if f.Profile != "" {
+        return f.Profile
+    }
+    return DefaultProfile
+}
+
+// parseINI parses sections of INI file into map of section name to its key value pairs
+func parseINI(content []byte) (map[string]map[string]string, error) {
+    sections := make(map[string]map[string]string)
+    var section map[string]string
+
+    scanner := bufio.NewScanner(bytes.NewReader(content))
+    for lineNumber := 1; scanner.Scan(); lineNumber++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+            name := strings.TrimSpace(line[1 : len(line)-1])
+            section = make(map[string]string)
+            sections[name] = section
+            continue
+        }
+        key, value, found := strings.Cut(line, "=")
+        if !found || section == nil {
+            return nil, fmt.Errorf("invalid line %d", lineNumber)
+        }
+        section[strings.TrimSpace(key)] = strings.TrimSpace(value)
+    }
+    return sections, scanner.Err()
+}
+
+// CredentialProcess runs external command which prints credentials as JSON object
+// with the same keys as provider arguments, e.g. {"access_key": "...", "secret_key": "..."}
+type CredentialProcess struct {
+    Command string
+}
+
+func (p *CredentialProcess) Name() string {
+    return "credential_process"
+}
+
+func (p *CredentialProcess) Retrieve(ctx context.Context) (*Credentials, error) {
+    if p.Command == "" {
+        return nil, nil
+    }
+
+    var cmd *exec.Cmd
+    if runtime.GOOS == "windows" {
+        cmd = exec.CommandContext(ctx, "cmd", "/C", p.Command)
+    } else {
+        cmd = exec.CommandContext(ctx, "sh", "-c", p.Command)
+    }
+    stderr := bytes.Buffer{}
+    cmd.Stderr = &stderr
+    output, err := cmd.Output()
+    if err != nil {
+        return nil, fmt.Errorf("command failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+    }
+
+    creds := &Credentials{}
+    if err := json.Unmarshal(output, creds); err != nil {
+        return nil, fmt.Errorf("can not parse command output: %v", err)
+    }
+    return creds, nil
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestCredentialChain_Retrieve(t *testing.T) {
+    credentialsFile := filepath.Join(t.TempDir(), "credentials")
+    content := `
+# comment
+[default]
+url = https://default/maestro/api/V3
+access_key = default-access-key
+secret_key = default-secret-key
+user_identifier = default@example.com
+
+[prod]
+url = https://prod/maestro/api/V3
+access_key = prod-access-key
+secret_key = prod-secret-key
+user_identifier = prod@example.com
+tenant = PROD
+`
+    if err := os.WriteFile(credentialsFile, []byte(content), 0600); err != nil {
+        t.Fatal(err)
+    }
+    malformedFile := filepath.Join(t.TempDir(), "malformed")
+    if err := os.WriteFile(malformedFile, []byte("not an ini file"), 0600); err != nil {
+        t.Fatal(err)
+    }
+
+    type TestCase struct {
+        Name    string
+        Env     map[string]string
+        Chain   CredentialChain
+        WantErr string
+        Want    *Credentials
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK provider arguments take precedence over environment",
+            Env: map[string]string{
+                "TERRAFORM_M3_URL":             "https://env/maestro/api/V3",
+                "TERRAFORM_M3_USER_IDENTIFIER": "env@example.com",
+                "TERRAFORM_M3_ACCESS_KEY":      "env-access-key",
+                "TERRAFORM_M3_SECRET_KEY":      "env-secret-key",
+                "TERRAFORM_M3_REGION_NAME":     "ENV-REGION",
+            },
+            Chain: CredentialChain{
+                &StaticCredentials{Credentials: Credentials{URL: "https://arg/maestro/api/V3", UserIdentifier: "arg@example.com"}},
+                &EnvCredentials{},
+            },
+            Want: &Credentials{
+                URL:            "https://arg/maestro/api/V3",
+                AccessKey:      "env-access-key",
+                SecretKey:      "env-secret-key",
+                UserIdentifier: "arg@example.com",
+                RegionName:     "ENV-REGION",
+            },
+        },
+
+        {
+            Name: "OK later sources are not read if provider arguments are complete",
+            Chain: CredentialChain{
+                &StaticCredentials{Credentials: Credentials{
+                    URL:            "https://arg/maestro/api/V3",
+                    AccessKey:      "arg-access-key",
+                    SecretKey:      "arg-secret-key",
+                    UserIdentifier: "arg@example.com",
+                }},
+                &SharedCredentialsFile{Path: malformedFile},
+                &CredentialProcess{Command: "exit 1"},
+            },
+            Want: &Credentials{
+                URL:            "https://arg/maestro/api/V3",
+                AccessKey:      "arg-access-key",
+                SecretKey:      "arg-secret-key",
+                UserIdentifier: "arg@example.com",
+            },
+        },
+
+        {
+            Name: "Got error if access key and secret key are set in different sources",
+            Env: map[string]string{
+                "TERRAFORM_M3_ACCESS_KEY": "env-access-key",
+                "TERRAFORM_M3_SECRET_KEY": "env-secret-key",
+            },
+            Chain: CredentialChain{
+                &StaticCredentials{Credentials: Credentials{URL: "https://arg/maestro/api/V3", SecretKey: "arg-secret-key"}},
+                &EnvCredentials{},
+            },
+            WantErr: "provider arguments: access_key and secret_key must be set together",
+        },
+
+        {
+            Name: "OK profile is read from shared credentials file",
+            Chain: CredentialChain{
+                &StaticCredentials{},
+                &EnvCredentials{},
+                &SharedCredentialsFile{Path: credentialsFile, Profile: "prod"},
+            },
+            Want: &Credentials{
+                URL:            "https://prod/maestro/api/V3",
+                AccessKey:      "prod-access-key",
+                SecretKey:      "prod-secret-key",
+                UserIdentifier: "prod@example.com",
+                TenantName:     "PROD",
+            },
+        },
+
+        {
+            Name: "OK credential process output is used",
+            Chain: CredentialChain{
+                &StaticCredentials{Credentials: Credentials{URL: "https://arg/maestro/api/V3"}},
+                &CredentialProcess{Command: `echo '{"access_key": "process-access-key", "secret_key": "process-secret-key", "user_identifier": "process@example.com"}'`},
+            },
+            Want: &Credentials{
+                URL:            "https://arg/maestro/api/V3",
+                AccessKey:      "process-access-key",
+                SecretKey:      "process-secret-key",
+                UserIdentifier: "process@example.com",
+            },
+        },
+
+        {
+            Name: "Got error with checked sources if values are missing",
+            Chain: CredentialChain{
+                &StaticCredentials{Credentials: Credentials{URL: "https://arg/maestro/api/V3"}},
+                &EnvCredentials{},
+            },
+            WantErr: "user_identifier, access_key, secret_key are not set, checked sources: provider arguments, environment variables",
+        },
+
+        {
+            Name: "Got error if profile is not found",
+            Chain: CredentialChain{
+                &SharedCredentialsFile{Path: credentialsFile, Profile: "missing"},
+            },
+            WantErr: "profile 'missing' is not found",
+        },
+
+        {
+            Name: "Got error if credential process fails",
+            Chain: CredentialChain{
+                &CredentialProcess{Command: "exit 1"},
+            },
+            WantErr: "credential_process: command failed",
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            for _, name := range []string{"TERRAFORM_M3_URL", "TERRAFORM_M3_USER_IDENTIFIER", "TERRAFORM_M3_ACCESS_KEY",
+                "TERRAFORM_M3_SECRET_KEY", "TERRAFORM_M3_TENANT_NAME", "TERRAFORM_M3_REGION_NAME", "TERRAFORM_M3_CLOUD"} {
+                t.Setenv(name, testCase.Env[name])
+            }
+
+            creds, err := testCase.Chain.Retrieve(context.Background())
+
+            if testCase.WantErr != "" {
+                if err == nil || !strings.Contains(err.Error(), testCase.WantErr) {
+                    t.Fatalf("got error '%v' instead of '%s'", err, testCase.WantErr)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if *creds != *testCase.Want {
+                t.Fatalf("got %+v instead of %+v", creds, testCase.Want)
+            }
+        })
+    }
+}
@@ -5,6 +5,7 @@
 package cmock
 
 import (
+	context "context"
 	reflect "reflect"
 	client "terraform-provider-m3/client"
 
@@ -35,18 +36,33 @@ func (m *MockTransporter) EXPECT() *MockTransporterMockRecorder {
 }
 
 // Do mocks base method.
-func (m *MockTransporter) Do(body interface{}) (*client.M3BatchResult, error) {
+func (m *MockTransporter) Do(ctx context.Context, body interface{}) (*client.M3BatchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Do", body)
+	ret := m.ctrl.Call(m, "Do", ctx, body)
 	ret0, _ := ret[0].(*client.M3BatchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Do indicates an expected call of Do.
-func (mr *MockTransporterMockRecorder) Do(body interface{}) *gomock.Call {
+func (mr *MockTransporterMockRecorder) Do(ctx, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTransporter)(nil).Do), body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTransporter)(nil).Do), ctx, body)
+}
+
+// DoBatch mocks base method.
+func (m *MockTransporter) DoBatch(ctx context.Context, payloads []*client.DefaultPayload) (*client.M3BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoBatch", ctx, payloads)
+	ret0, _ := ret[0].(*client.M3BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoBatch indicates an expected call of DoBatch.
+func (mr *MockTransporterMockRecorder) DoBatch(ctx, payloads interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoBatch", reflect.TypeOf((*MockTransporter)(nil).DoBatch), ctx, payloads)
 }
 
 // MakePayload mocks base method.
@@ -0,0 +1,104 @@
+package client
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strings"
+)
+
+const redactedValue = "***"
+
+// DefaultSensitiveFields contains JSON fields which values are never written to logs
+var DefaultSensitiveFields = []string{
+    "publicKey",
+    "publicPart",
+    "privatePart",
+    "content",
+    "secretKey",
+    "accessKey",
+}
+
+// sensitiveHeaders contains headers which values are never written to logs
+var sensitiveHeaders = []string{
+    headerAuthentication,
+    headerAccessKey,
+}
+
+// redactor masks values of sensitive fields in JSON documents
+type redactor struct {
+    fields map[string]bool
+}
+
+func newRedactor(extraFields []string) *redactor {
+    fields := make(map[string]bool, len(DefaultSensitiveFields)+len(extraFields))
+    for _, field := range DefaultSensitiveFields {
+        fields[strings.ToLower(field)] = true
+    }
+    for _, field := range extraFields {
+        fields[strings.ToLower(field)] = true
+    }
+    return &redactor{fields: fields}
+}
+
+// JSON returns indented document with masked sensitive fields.
+// Request and response bodies contain JSON encoded into strings, such strings are redacted as well
+func (r *redactor) JSON(data []byte) string {
+    var document interface{}
+    if err := json.Unmarshal(data, &document); err != nil {
+        return fmt.Sprintf("<%d bytes of non JSON data>", len(data))
+    }
+    redacted, err := json.MarshalIndent(r.value(document), "", "\t")
+    if err != nil {
+        return fmt.Sprintf("<%d bytes of non JSON data>", len(data))
+    }
+    return string(redacted)
+}
+
+// Headers returns copy of headers with masked sensitive values
+func (r *redactor) Headers(headers http.Header) map[string]string {
+    redacted := make(map[string]string, len(headers))
+    for name := range headers {
+        redacted[name] = headers.Get(name)
+    }
+    for _, name := range sensitiveHeaders {
+        if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+            redacted[http.CanonicalHeaderKey(name)] = redactedValue
+        }
+    }
+    return redacted
+}
+
+func (r *redactor) value(value interface{}) interface{} {
+    switch v := value.(type) {
+    case map[string]interface{}:
+        for key, nested := range v {
+            if r.fields[strings.ToLower(key)] {
+                v[key] = redactedValue
+                continue
+            }
+            v[key] = r.value(nested)
+        }
+        return v
+    case []interface{}:
+        for i, nested := range v {
+            v[i] = r.value(nested)
+        }
+        return v
+    case string:
+        trimmed := strings.TrimSpace(v)
+        if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+            return v
+        }
+        var document interface{}
+        if err := json.Unmarshal([]byte(trimmed), &document); err != nil {
+            return v
+        }
+        encoded, err := json.Marshal(r.value(document))
+        if err != nil {
+            return redactedValue
+        }
+        return string(encoded)
+    }
+    return value
+}
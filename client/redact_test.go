@@ -0,0 +1,81 @@
+package client
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+)
+
+func TestRedactor_JSON(t *testing.T) {
+    type TestCase struct {
+        Name        string
+        Data        string
+        ExtraFields []string
+        Hidden      []string
+        Visible     []string
+    }
+
+    testTable := []TestCase{
+        {
+            Name:    "OK fields in request body string are masked",
+            Data:    `[{"id":"1","type":"ADD_KEY","params":{"body":"{\"name\":\"key-name\",\"publicKey\":\"ssh-rsa AAAA\"}"}}]`,
+            Hidden:  []string{"ssh-rsa AAAA"},
+            Visible: []string{"key-name", "ADD_KEY"},
+        },
+
+        {
+            Name:    "OK fields in response data string are masked",
+            Data:    `{"results":[{"id":"1","data":"[{\"fileName\":\"run.sh\",\"content\":\"echo secret\"}]"}]}`,
+            Hidden:  []string{"echo secret"},
+            Visible: []string{"run.sh"},
+        },
+
+        {
+            Name:        "OK configured fields are masked",
+            Data:        `{"additionalData":{"chefToken":"token-value"},"instanceName":"name"}`,
+            ExtraFields: []string{"chefToken"},
+            Hidden:      []string{"token-value"},
+            Visible:     []string{"name"},
+        },
+
+        {
+            Name:   "OK non JSON data is not written",
+            Data:   `ssh-rsa AAAA`,
+            Hidden: []string{"ssh-rsa AAAA"},
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            redacted := newRedactor(testCase.ExtraFields).JSON([]byte(testCase.Data))
+
+            for _, value := range testCase.Hidden {
+                if strings.Contains(redacted, value) {
+                    t.Fatalf("value '%s' is not masked in %s", value, redacted)
+                }
+            }
+            for _, value := range testCase.Visible {
+                if !strings.Contains(redacted, value) {
+                    t.Fatalf("value '%s' is masked in %s", value, redacted)
+                }
+            }
+        })
+    }
+}
+
+func TestRedactor_Headers(t *testing.T) {
+    headers := http.Header{}
+    headers.Add(headerAuthentication, "signature")
+    headers.Add(headerAccessKey, "access key")
+    headers.Add(headerDate, "1600000000000")
+
+    redacted := newRedactor(nil).Headers(headers)
+
+    if redacted[http.CanonicalHeaderKey(headerAuthentication)] != redactedValue ||
+        redacted[http.CanonicalHeaderKey(headerAccessKey)] != redactedValue {
+        t.Fatal("signature headers are not masked")
+    }
+    if redacted[http.CanonicalHeaderKey(headerDate)] != "1600000000000" {
+        t.Fatal("header without sensitive value is masked")
+    }
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "math/rand"
+    "net"
+    "net/http"
+    "strconv"
+    "syscall"
+    "time"
+)
+
+// RetryPolicy describes which failed requests are repeated and how long to wait between attempts
+type RetryPolicy struct {
+    MaxRetries int
+    MinWait    time.Duration
+    MaxWait    time.Duration
+    // NonIdempotent contains action types which are repeated only when request was not processed by server
+    NonIdempotent map[string]bool
+}
+
+// StatusError is returned when server responds with status code other than 200
+type StatusError struct {
+    StatusCode int
+    Body       string
+    RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+    return fmt.Sprintf("got status code %v instead of 200\nBody: %s", e.StatusCode, e.Body)
+}
+
+// idempotent reports whether every action in request data may be safely repeated
+func (p *RetryPolicy) idempotent(requestData []interface{}) bool {
+    for _, body := range requestData {
+        payload, ok := body.(*DefaultPayload)
+        if !ok || p.NonIdempotent[payload.Type] {
+            return false
+        }
+    }
+    return true
+}
+
+// retryableError classifies error of a single round trip, it returns true and the delay requested by server
+// if request should be repeated
+func (p *RetryPolicy) retryableError(err error, idempotent bool) (bool, time.Duration) {
+    var statusErr *StatusError
+    if errors.As(err, &statusErr) {
+        switch {
+        case statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode == http.StatusServiceUnavailable:
+            // request was rejected before processing
+            return true, statusErr.RetryAfter
+        case statusErr.StatusCode >= 500:
+            return idempotent, statusErr.RetryAfter
+        }
+        return false, 0
+    }
+
+    var dnsErr *net.DNSError
+    if errors.As(err, &dnsErr) {
+        return true, 0
+    }
+    var opErr *net.OpError
+    if errors.As(err, &opErr) && opErr.Op == "dial" {
+        // connection was not established, so request did not reach server
+        return true, 0
+    }
+
+    var netErr net.Error
+    if errors.As(err, &netErr) && netErr.Timeout() {
+        return idempotent, 0
+    }
+    if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+        return idempotent, 0
+    }
+    return false, 0
+}
+
+// retryableResult reports whether action should be repeated because of status code of its result.
+// Result of a single action does not tell whether it was rejected before processing,
+// so throttled actions are repeated only when they are idempotent as well as failed ones
+func (p *RetryPolicy) retryableResult(result *M3RawResult, method string) bool {
+    if result.StatusCode == http.StatusTooManyRequests || result.StatusCode >= 500 {
+        return !p.NonIdempotent[method]
+    }
+    return false
+}
+
+// retryBudget counts retries of one action, repeated round trips and repeated results
+// share it, so total number of attempts never exceeds MaxRetries + 1
+type retryBudget struct {
+    attempt int
+    max     int
+}
+
+// newBudget returns budget of MaxRetries retries
+func (p *RetryPolicy) newBudget() *retryBudget {
+    return &retryBudget{max: p.MaxRetries}
+}
+
+// next takes one retry from budget, it returns number of the retry and false if budget is exhausted
+func (b *retryBudget) next() (int, bool) {
+    if b.attempt >= b.max {
+        return b.attempt, false
+    }
+    b.attempt++
+    return b.attempt, true
+}
+
+// backoff returns exponential delay with jitter for attempt, retryAfter is used instead when server asked for it.
+// Delay never exceeds MaxWait
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+    if retryAfter > 0 {
+        if p.MaxWait > 0 && retryAfter > p.MaxWait {
+            return p.MaxWait
+        }
+        return retryAfter
+    }
+
+    delay := p.MinWait
+    for i := 1; i < attempt && (p.MaxWait <= 0 || delay < p.MaxWait); i++ {
+        delay *= 2
+    }
+    if p.MaxWait > 0 && delay > p.MaxWait {
+        delay = p.MaxWait
+    }
+    if delay <= 0 {
+        return 0
+    }
+    // full delay is halved and the rest is random to spread retries of parallel requests
+    return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses Retry-After header which contains either seconds or HTTP date
+func parseRetryAfter(value string) time.Duration {
+    if value == "" {
+        return 0
+    }
+    if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+        return time.Duration(seconds) * time.Second
+    }
+    if date, err := http.ParseTime(value); err == nil {
+        if delay := time.Until(date); delay > 0 {
+            return delay
+        }
+    }
+    return 0
+}
+
+// sleep waits for delay or until context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-time.After(delay):
+        return nil
+    }
+}
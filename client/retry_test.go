@@ -0,0 +1,162 @@
+package client
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestTransport_DoRetry(t *testing.T) {
+    type TestCase struct {
+        Name string
+        // Statuses contains HTTP status codes returned by server one by one, 200 is returned when they run out
+        Statuses []int
+        // ResultCodes contains status codes of result returned in successful responses one by one
+        ResultCodes []int
+        Method      string
+        WantErr     bool
+        WantCalls   int
+    }
+
+    testTable := []TestCase{
+        {
+            Name:      "OK idempotent request is retried on server error",
+            Statuses:  []int{502, 504},
+            Method:    "DESCRIBE_INSTANCE",
+            WantErr:   false,
+            WantCalls: 3,
+        },
+
+        {
+            Name:      "OK non-idempotent request is retried when throttled",
+            Statuses:  []int{429, 503},
+            Method:    "RUN_INSTANCE",
+            WantErr:   false,
+            WantCalls: 3,
+        },
+
+        {
+            Name:      "Got error if non-idempotent request failed on server",
+            Statuses:  []int{502},
+            Method:    "RUN_INSTANCE",
+            WantErr:   true,
+            WantCalls: 1,
+        },
+
+        {
+            Name:      "Got error if client error is returned",
+            Statuses:  []int{400},
+            Method:    "DESCRIBE_INSTANCE",
+            WantErr:   true,
+            WantCalls: 1,
+        },
+
+        {
+            Name:      "Got error if retries run out",
+            Statuses:  []int{500, 500, 500, 500, 500},
+            Method:    "DESCRIBE_INSTANCE",
+            WantErr:   true,
+            WantCalls: 3,
+        },
+
+        {
+            Name:        "OK throttled result is retried",
+            ResultCodes: []int{429, 200},
+            Method:      "DESCRIBE_INSTANCE",
+            WantErr:     false,
+            WantCalls:   2,
+        },
+
+        {
+            Name:        "OK throttled result of non-idempotent action is not retried",
+            ResultCodes: []int{429, 200},
+            Method:      "RUN_INSTANCE",
+            WantErr:     false,
+            WantCalls:   1,
+        },
+
+        {
+            Name:        "OK failed round trips and failed results share retries",
+            Statuses:    []int{502},
+            ResultCodes: []int{500, 500, 500, 500},
+            Method:      "DESCRIBE_INSTANCE",
+            WantErr:     false,
+            WantCalls:   3,
+        },
+
+        {
+            Name:        "OK failed result of non-idempotent action is not retried",
+            ResultCodes: []int{500, 200},
+            Method:      "RUN_INSTANCE",
+            WantErr:     false,
+            WantCalls:   1,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            conf := &Config{SecretKey: testSecretKey}
+            calls := 0
+            server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                calls++
+                if calls <= len(testCase.Statuses) {
+                    w.Header().Set("Retry-After", "0")
+                    w.WriteHeader(testCase.Statuses[calls-1])
+                    return
+                }
+                body, _ := io.ReadAll(r.Body)
+                decrypted, _ := conf.decrypt(body)
+                payloads := make([]*DefaultPayload, 0, 1)
+                _ = json.Unmarshal([]byte(decrypted), &payloads)
+
+                result := &M3RawResult{ID: payloads[0].ID, Status: "SUCCESS", StatusCode: 200}
+                if i := calls - len(testCase.Statuses) - 1; i < len(testCase.ResultCodes) {
+                    result.StatusCode = testCase.ResultCodes[i]
+                }
+                data, _ := json.Marshal(&M3BatchResult{Results: []*M3RawResult{result}})
+                encrypted, _ := conf.encrypt(data)
+                _, _ = w.Write([]byte(encrypted))
+            }))
+            defer server.Close()
+
+            conf.Client = server.Client()
+            conf.URL = server.URL
+            conf.Retry = RetryPolicy{
+                MaxRetries:    2,
+                MinWait:       time.Millisecond,
+                MaxWait:       5 * time.Millisecond,
+                NonIdempotent: map[string]bool{"RUN_INSTANCE": true},
+            }
+
+            _, err := NewTransport(conf).Do(context.Background(), &DefaultPayload{ID: "1", Type: testCase.Method, Params: &Params{Body: "{}"}})
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal(err)
+            }
+            if calls != testCase.WantCalls {
+                t.Fatalf("got %d calls instead of %d", calls, testCase.WantCalls)
+            }
+        })
+    }
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+    p := &RetryPolicy{MinWait: time.Second, MaxWait: 10 * time.Second}
+
+    for attempt := 1; attempt <= 10; attempt++ {
+        delay := p.backoff(attempt, 0)
+        if delay < 0 || delay > p.MaxWait {
+            t.Fatalf("delay %v of attempt %d is out of range", delay, attempt)
+        }
+    }
+    if delay := p.backoff(1, 3*time.Second); delay != 3*time.Second {
+        t.Fatalf("Retry-After is not honored, got %v", delay)
+    }
+    if delay := p.backoff(1, time.Minute); delay != p.MaxWait {
+        t.Fatalf("Retry-After is not limited by max wait, got %v", delay)
+    }
+}
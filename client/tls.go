@@ -0,0 +1,88 @@
+package client
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+)
+
+// TLSVersions maps allowed values of minimum TLS version to crypto/tls constants
+var TLSVersions = map[string]uint16{
+    "1.0": tls.VersionTLS10,
+    "1.1": tls.VersionTLS11,
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+// TLSOptions contains settings of TLS connection to Maestro API.
+// CABundle, ClientCertificate and ClientKey accept either path to a file or PEM encoded content
+type TLSOptions struct {
+    CABundle          string
+    ClientCertificate string
+    ClientKey         string
+    MinVersion        string
+    Insecure          bool
+}
+
+// NewTLSConfig builds tls.Config from options, server certificate is verified unless Insecure is set
+func NewTLSConfig(opts *TLSOptions) (*tls.Config, error) {
+    conf := &tls.Config{
+        MinVersion:         tls.VersionTLS12,
+        InsecureSkipVerify: opts.Insecure,
+    }
+
+    if opts.MinVersion != "" {
+        version, ok := TLSVersions[opts.MinVersion]
+        if !ok {
+            return nil, fmt.Errorf("unsupported minimum TLS version '%s'", opts.MinVersion)
+        }
+        conf.MinVersion = version
+    }
+
+    if opts.CABundle != "" {
+        bundle, err := readPEM(opts.CABundle)
+        if err != nil {
+            return nil, fmt.Errorf("can not read CA bundle: %v", err)
+        }
+        pool, err := x509.SystemCertPool()
+        if err != nil {
+            pool = x509.NewCertPool()
+        }
+        if !pool.AppendCertsFromPEM(bundle) {
+            return nil, errors.New("CA bundle does not contain any valid PEM encoded certificate")
+        }
+        conf.RootCAs = pool
+    }
+
+    if opts.ClientCertificate != "" || opts.ClientKey != "" {
+        if opts.ClientCertificate == "" || opts.ClientKey == "" {
+            return nil, errors.New("both client certificate and client key must be set")
+        }
+        certificate, err := readPEM(opts.ClientCertificate)
+        if err != nil {
+            return nil, fmt.Errorf("can not read client certificate: %v", err)
+        }
+        key, err := readPEM(opts.ClientKey)
+        if err != nil {
+            return nil, fmt.Errorf("can not read client key: %v", err)
+        }
+        pair, err := tls.X509KeyPair(certificate, key)
+        if err != nil {
+            return nil, fmt.Errorf("can not load client certificate: %v", err)
+        }
+        conf.Certificates = []tls.Certificate{pair}
+    }
+
+    return conf, nil
+}
+
+// readPEM returns value itself when it contains PEM block, otherwise value is treated as path to a file
+func readPEM(value string) ([]byte, error) {
+    if strings.Contains(value, "-----BEGIN") {
+        return []byte(value), nil
+    }
+    return os.ReadFile(value)
+}
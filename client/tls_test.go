@@ -0,0 +1,100 @@
+package client
+
+import (
+    "encoding/pem"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+
+    caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+    caFile := filepath.Join(t.TempDir(), "ca.pem")
+    if err := os.WriteFile(caFile, []byte(caBundle), 0600); err != nil {
+        t.Fatal(err)
+    }
+
+    type TestCase struct {
+        Name          string
+        Options       *TLSOptions
+        WantErr       bool
+        WantConnected bool
+    }
+
+    testTable := []TestCase{
+        {
+            Name:          "OK CA bundle as PEM content",
+            Options:       &TLSOptions{CABundle: caBundle},
+            WantConnected: true,
+        },
+
+        {
+            Name:          "OK CA bundle as file",
+            Options:       &TLSOptions{CABundle: caFile, MinVersion: "1.3"},
+            WantConnected: true,
+        },
+
+        {
+            Name:          "OK insecure skips verification",
+            Options:       &TLSOptions{Insecure: true},
+            WantConnected: true,
+        },
+
+        {
+            Name:          "Got connection error if certificate is not trusted",
+            Options:       &TLSOptions{},
+            WantConnected: false,
+        },
+
+        {
+            Name:    "Got error if CA bundle file does not exist",
+            Options: &TLSOptions{CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+            WantErr: true,
+        },
+
+        {
+            Name:    "Got error if CA bundle contains no certificates",
+            Options: &TLSOptions{CABundle: "-----BEGIN CERTIFICATE-----\nbroken\n-----END CERTIFICATE-----"},
+            WantErr: true,
+        },
+
+        {
+            Name:    "Got error if client key is missing",
+            Options: &TLSOptions{ClientCertificate: caBundle},
+            WantErr: true,
+        },
+
+        {
+            Name:    "Got error if TLS version is unsupported",
+            Options: &TLSOptions{MinVersion: "0.9"},
+            WantErr: true,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            conf, err := NewTLSConfig(testCase.Options)
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal(err)
+            }
+            if err != nil {
+                return
+            }
+
+            c := &http.Client{Transport: &http.Transport{TLSClientConfig: conf}}
+            resp, err := c.Get(server.URL)
+            if err == nil {
+                _ = resp.Body.Close()
+            }
+            if testCase.WantConnected != (err == nil) {
+                t.Fatal(err)
+            }
+        })
+    }
+}
@@ -2,38 +2,32 @@ package client
 
 import (
     "bytes"
+    "context"
     "encoding/json"
+    "errors"
     "fmt"
+    "github.com/hashicorp/terraform-plugin-log/tflog"
     "io"
-    "log"
     "net/http"
     "strconv"
     "time"
 )
 
 type Transport struct {
-    config *Config
+    config   *Config
+    redactor *redactor
 }
 
 func NewTransport(conf *Config) *Transport {
-    return &Transport{config: conf}
+    return &Transport{config: conf, redactor: newRedactor(conf.SensitiveFields)}
 }
 
 // createRequest need for build http request from JSON
-func (t *Transport) createRequest(body interface{}) (*http.Request, error) {
-    var requestData []interface{}
-    requestData = append(requestData, body)
+func (t *Transport) createRequest(ctx context.Context, url string, requestData interface{}) (*http.Request, error) {
     requestDataJSON, err := json.Marshal(requestData)
     if err != nil {
         return nil, fmt.Errorf("%v: %v", "can not serialize request", err)
     }
-    out := bytes.Buffer{}
-    err = json.Indent(&out, requestDataJSON, "", "\t")
-    if err != nil {
-        log.Fatalf("Failed to indent JSON: %v", err)
-    }
-    log.Printf("Request data:\n%s\n", out.String())
-
 
     encryptedRequestBody, err := t.config.encrypt(requestDataJSON)
     if err != nil {
@@ -41,7 +35,7 @@ func (t *Transport) createRequest(body interface{}) (*http.Request, error) {
     }
 
     date := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
-    req, err := http.NewRequest(method, t.config.URL, bytes.NewReader([]byte(encryptedRequestBody)))
+    req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader([]byte(encryptedRequestBody)))
     if err != nil {
         return nil, fmt.Errorf("%v: %v", "can not create request", err)
     }
@@ -53,24 +47,217 @@ func (t *Transport) createRequest(body interface{}) (*http.Request, error) {
     req.Header.Add(headerDate, date)
     req.Header.Add(headerAccessKey, t.config.AccessKey)
     req.Header.Add(headerSdkVersion, valueSdkVersion)
-    req.Header.Add(headerAsync, valueAsync)
+    req.Header.Add(headerAsync, strconv.FormatBool(t.config.Async.Enabled))
     req.Header.Add(headerUserIdentifier, t.config.UserIdentifier)
 
     req.Close = true
 
+    tflog.Trace(ctx, "Maestro3 API request", map[string]interface{}{
+        "url":     url,
+        "headers": t.redactor.Headers(req.Header),
+        "body":    t.redactor.JSON(requestDataJSON),
+    })
     return req, nil
 }
 
 // Do executes action on remote agent
-func (t *Transport) Do(body interface{}) (*M3BatchResult, error) {
-    req, err := t.createRequest(body)
+func (t *Transport) Do(ctx context.Context, body interface{}) (*M3BatchResult, error) {
+    return t.do(ctx, []interface{}{body})
+}
+
+// DoBatch executes several actions on remote agent within one request.
+// Results are returned in the same order as payloads, matched by payload ID
+func (t *Transport) DoBatch(ctx context.Context, payloads []*DefaultPayload) (*M3BatchResult, error) {
+    if len(payloads) == 0 {
+        return nil, errors.New("batch is empty")
+    }
+
+    requestData := make([]interface{}, 0, len(payloads))
+    for _, payload := range payloads {
+        if payload == nil {
+            return nil, errors.New("batch contains nil payload")
+        }
+        requestData = append(requestData, payload)
+    }
+
+    r, err := t.do(ctx, requestData)
+    if err != nil {
+        return nil, err
+    }
+
+    resultsByID := make(map[string]*M3RawResult, len(r.Results))
+    for _, result := range r.Results {
+        if result != nil {
+            resultsByID[result.ID] = result
+        }
+    }
+
+    results := make([]*M3RawResult, 0, len(payloads))
+    for _, payload := range payloads {
+        result, ok := resultsByID[payload.ID]
+        if !ok {
+            return nil, fmt.Errorf("no result for action '%s' with id '%s' in response", payload.Type, payload.ID)
+        }
+        results = append(results, result)
+    }
+    return &M3BatchResult{Results: results}, nil
+}
+
+func (t *Transport) do(ctx context.Context, requestData []interface{}) (*M3BatchResult, error) {
+    budget := t.config.Retry.newBudget()
+    r, err := t.execute(ctx, requestData, budget)
+    if err != nil {
+        return nil, err
+    }
+
+    // actions which were throttled or failed on server side are repeated separately
+    for {
+        retryData := make([]interface{}, 0, len(requestData))
+        for _, body := range requestData {
+            payload, ok := body.(*DefaultPayload)
+            if !ok {
+                continue
+            }
+            for _, result := range r.Results {
+                if result != nil && result.ID == payload.ID && t.config.Retry.retryableResult(result, payload.Type) {
+                    retryData = append(retryData, payload)
+                }
+            }
+        }
+        if len(retryData) == 0 {
+            break
+        }
+        attempt, ok := budget.next()
+        if !ok {
+            break
+        }
+
+        delay := t.config.Retry.backoff(attempt, 0)
+        tflog.Debug(ctx, "Retrying failed actions", map[string]interface{}{
+            "actions": len(retryData),
+            "delay":   delay.String(),
+            "attempt": attempt,
+        })
+        if err := sleep(ctx, delay); err != nil {
+            return nil, err
+        }
+        retried, err := t.execute(ctx, retryData, budget)
+        if err != nil {
+            return nil, err
+        }
+        mergeResults(r, retried)
+    }
+    return r, nil
+}
+
+// execute sends request data repeating failed round trips within budget and awaits async results
+func (t *Transport) execute(ctx context.Context, requestData []interface{}, budget *retryBudget) (*M3BatchResult, error) {
+    r, err := t.sendWithRetry(ctx, t.config.Client, t.config.URL, requestData, t.config.Retry.idempotent(requestData), budget)
     if err != nil {
         return nil, err
     }
-    log.Println(req)
-    resp, err := t.config.Client.Do(req)
+    if !t.config.Async.Enabled {
+        return r, nil
+    }
+    return t.awaitResults(ctx, r)
+}
+
+// sendWithRetry sends request data and repeats it according to retry policy while budget allows,
+// non-idempotent request is repeated only if it was not processed by server
+func (t *Transport) sendWithRetry(ctx context.Context, client *http.Client, url string, requestData interface{}, idempotent bool, budget *retryBudget) (*M3BatchResult, error) {
+    for {
+        r, err := t.send(ctx, client, url, requestData)
+        if err == nil {
+            return r, nil
+        }
+        if ctx.Err() != nil {
+            return nil, err
+        }
+        retryable, retryAfter := t.config.Retry.retryableError(err, idempotent)
+        if !retryable {
+            return nil, err
+        }
+        attempt, ok := budget.next()
+        if !ok {
+            return nil, err
+        }
+
+        delay := t.config.Retry.backoff(attempt, retryAfter)
+        tflog.Debug(ctx, "Retrying failed request", map[string]interface{}{
+            "error":   err.Error(),
+            "delay":   delay.String(),
+            "attempt": attempt,
+        })
+        if err := sleep(ctx, delay); err != nil {
+            return nil, err
+        }
+    }
+}
+
+// mergeResults replaces results in r by results with the same ID from update
+func mergeResults(r *M3BatchResult, update *M3BatchResult) {
+    updateByID := make(map[string]*M3RawResult, len(update.Results))
+    for _, result := range update.Results {
+        if result != nil {
+            updateByID[result.ID] = result
+        }
+    }
+    for i, result := range r.Results {
+        if result == nil {
+            continue
+        }
+        if updated, ok := updateByID[result.ID]; ok {
+            r.Results[i] = updated
+        }
+    }
+}
+
+// awaitResults polls results of actions which are still processed by server in async mode,
+// polls are limited by async timeout instead of timeout of HTTP client
+func (t *Transport) awaitResults(ctx context.Context, r *M3BatchResult) (*M3BatchResult, error) {
+    deadline := time.Now().Add(t.config.Async.Timeout)
+    ctx, cancel := context.WithDeadline(ctx, deadline)
+    defer cancel()
+    pollClient := *t.config.Client
+    pollClient.Timeout = 0
+    for {
+        pending := make([]string, 0, len(r.Results))
+        for _, result := range r.Results {
+            if result != nil && result.Status == StatusInProgress {
+                pending = append(pending, result.ID)
+            }
+        }
+        if len(pending) == 0 {
+            return r, nil
+        }
+        if time.Now().Add(t.config.Async.PollInterval).After(deadline) {
+            return nil, fmt.Errorf("async requests %v are not completed within %v", pending, t.config.Async.Timeout)
+        }
+        if err := sleep(ctx, t.config.Async.PollInterval); err != nil {
+            return nil, err
+        }
+
+        // every poll is a separate request with its own budget
+        polled, err := t.sendWithRetry(ctx, &pollClient, t.config.URL+resultsPath, &AsyncResultsRequest{RequestIds: pending}, true, t.config.Retry.newBudget())
+        if err != nil {
+            if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+                return nil, fmt.Errorf("async requests %v are not completed within %v: %w", pending, t.config.Async.Timeout, err)
+            }
+            return nil, err
+        }
+        mergeResults(r, polled)
+    }
+}
+
+// send encrypts request data, posts it to url by client and decrypts response
+func (t *Transport) send(ctx context.Context, client *http.Client, url string, requestData interface{}) (*M3BatchResult, error) {
+    req, err := t.createRequest(ctx, url, requestData)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := client.Do(req)
     if err != nil {
-        return nil, fmt.Errorf("%v: %v", "failed to process request", err)
+        return nil, fmt.Errorf("%v: %w", "failed to process request", err)
     }
 
     defer func() {
@@ -81,12 +268,15 @@ func (t *Transport) Do(body interface{}) (*M3BatchResult, error) {
 
     respBody, err := io.ReadAll(resp.Body)
     if err != nil {
-        return nil, fmt.Errorf("%v: %v", "failed to process response", err)
+        return nil, fmt.Errorf("%v: %w", "failed to process response", err)
     }
 
     if resp.StatusCode != 200 {
-        return nil, fmt.Errorf("got status code %v instead of 200\nBody: %s",
-            resp.StatusCode, string(respBody))
+        return nil, &StatusError{
+            StatusCode: resp.StatusCode,
+            Body:       string(respBody),
+            RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+        }
     }
 
     decryptedResponse, err := t.config.decrypt(respBody)
@@ -94,12 +284,10 @@ func (t *Transport) Do(body interface{}) (*M3BatchResult, error) {
         return nil, err
     }
 
-    out := bytes.Buffer{}
-    err = json.Indent(&out, []byte(decryptedResponse), "", "\t")
-    if err != nil {
-        log.Fatalf("Failed to indent JSON: %v", err)
-    }
-    log.Printf("Response data:\n%s\n", out.String())
+    tflog.Trace(ctx, "Maestro3 API response", map[string]interface{}{
+        "url":  url,
+        "body": t.redactor.JSON([]byte(decryptedResponse)),
+    })
 
     result := new(M3BatchResult)
     err = json.Unmarshal([]byte(decryptedResponse), &result)
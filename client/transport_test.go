@@ -0,0 +1,197 @@
+package client
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+const testSecretKey = "0123456789abcdef0123456789abcdef"
+
+// newTestServer starts server which decrypts request and answers with results built by respond
+func newTestServer(t *testing.T, respond func(path string, body []byte) *M3BatchResult) (*httptest.Server, *Config) {
+    conf := &Config{SecretKey: testSecretKey}
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, err := io.ReadAll(r.Body)
+        if err != nil {
+            t.Fatal(err)
+        }
+        decrypted, err := conf.decrypt(body)
+        if err != nil {
+            t.Fatal(err)
+        }
+        data, _ := json.Marshal(respond(r.URL.Path, []byte(decrypted)))
+        encrypted, err := conf.encrypt(data)
+        if err != nil {
+            t.Fatal(err)
+        }
+        _, _ = w.Write([]byte(encrypted))
+    }))
+
+    conf.Client = server.Client()
+    conf.URL = server.URL
+    return server, conf
+}
+
+// batchResponder decodes batch request and passes payloads to respond
+func batchResponder(t *testing.T, respond func(payloads []*DefaultPayload) *M3BatchResult) func(string, []byte) *M3BatchResult {
+    return func(path string, body []byte) *M3BatchResult {
+        payloads := make([]*DefaultPayload, 0, 2)
+        if err := json.Unmarshal(body, &payloads); err != nil {
+            t.Fatal(err)
+        }
+        return respond(payloads)
+    }
+}
+
+func TestTransport_DoBatch(t *testing.T) {
+    type TestCase struct {
+        Name     string
+        WantErr  bool
+        Payloads []*DefaultPayload
+        Respond  func(payloads []*DefaultPayload) *M3BatchResult
+    }
+
+    testTable := []TestCase{
+        {
+            Name:    "OK results matched by ID",
+            WantErr: false,
+            Payloads: []*DefaultPayload{
+                {ID: "1", Type: "DESCRIBE_INSTANCE", Params: &Params{Body: "{}"}},
+                {ID: "2", Type: "DESCRIBE_VOLUME", Params: &Params{Body: "{}"}},
+                {ID: "3", Type: "DESCRIBE_IMAGE", Params: &Params{Body: "{}"}},
+            },
+            Respond: func(payloads []*DefaultPayload) *M3BatchResult {
+                results := make([]*M3RawResult, 0, len(payloads))
+                for i := len(payloads) - 1; i >= 0; i-- {
+                    results = append(results, &M3RawResult{ID: payloads[i].ID, Status: "SUCCESS", Data: payloads[i].Type})
+                }
+                return &M3BatchResult{Results: results}
+            },
+        },
+
+        {
+            Name:    "Got error if result for payload is missing",
+            WantErr: true,
+            Payloads: []*DefaultPayload{
+                {ID: "1", Type: "DESCRIBE_INSTANCE", Params: &Params{Body: "{}"}},
+                {ID: "2", Type: "DESCRIBE_VOLUME", Params: &Params{Body: "{}"}},
+            },
+            Respond: func(payloads []*DefaultPayload) *M3BatchResult {
+                return &M3BatchResult{Results: []*M3RawResult{{ID: payloads[0].ID, Status: "SUCCESS"}}}
+            },
+        },
+
+        {
+            Name:     "Got error if batch is empty",
+            WantErr:  true,
+            Payloads: []*DefaultPayload{},
+            Respond: func(payloads []*DefaultPayload) *M3BatchResult {
+                return &M3BatchResult{}
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            server, conf := newTestServer(t, batchResponder(t, testCase.Respond))
+            defer server.Close()
+
+            r, err := NewTransport(conf).DoBatch(context.Background(), testCase.Payloads)
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal(err)
+            }
+            if err != nil {
+                return
+            }
+            for i, payload := range testCase.Payloads {
+                if r.Results[i].ID != payload.ID || r.Results[i].Data != payload.Type {
+                    t.Fatalf("result %d does not match payload %s", i, payload.ID)
+                }
+            }
+        })
+    }
+}
+
+func TestTransport_DoAsync(t *testing.T) {
+    type TestCase struct {
+        Name        string
+        WantErr     bool
+        PollsToDone int
+        // PollDelay is the time server takes to answer a poll
+        PollDelay time.Duration
+        // ClientTimeout is timeout of HTTP client, it does not limit polls
+        ClientTimeout time.Duration
+    }
+
+    testTable := []TestCase{
+        {
+            Name:        "OK result is polled until done",
+            WantErr:     false,
+            PollsToDone: 2,
+        },
+
+        {
+            Name:        "Got error if result is not done before timeout",
+            WantErr:     true,
+            PollsToDone: 100,
+        },
+
+        {
+            Name:          "OK poll is limited by async timeout instead of timeout of client",
+            WantErr:       false,
+            PollsToDone:   1,
+            PollDelay:     50 * time.Millisecond,
+            ClientTimeout: 20 * time.Millisecond,
+        },
+
+        {
+            Name:        "Got error if poll is not answered before timeout",
+            WantErr:     true,
+            PollsToDone: 1,
+            PollDelay:   500 * time.Millisecond,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            polls := 0
+            server, conf := newTestServer(t, func(path string, body []byte) *M3BatchResult {
+                if path != resultsPath {
+                    payloads := make([]*DefaultPayload, 0, 1)
+                    _ = json.Unmarshal(body, &payloads)
+                    return &M3BatchResult{Results: []*M3RawResult{{ID: payloads[0].ID, Status: StatusInProgress}}}
+                }
+                time.Sleep(testCase.PollDelay)
+                request := new(AsyncResultsRequest)
+                _ = json.Unmarshal(body, request)
+                polls++
+                if polls < testCase.PollsToDone {
+                    return &M3BatchResult{Results: []*M3RawResult{{ID: request.RequestIds[0], Status: StatusInProgress}}}
+                }
+                return &M3BatchResult{Results: []*M3RawResult{{ID: request.RequestIds[0], Status: "SUCCESS", Data: "done"}}}
+            })
+            defer server.Close()
+            conf.Client.Timeout = testCase.ClientTimeout
+            conf.Async = AsyncConfig{
+                Enabled:      true,
+                PollInterval: 10 * time.Millisecond,
+                Timeout:      200 * time.Millisecond,
+            }
+
+            r, err := NewTransport(conf).Do(context.Background(), &DefaultPayload{ID: "1", Type: "CREATE_IMAGE", Params: &Params{Body: "{}"}})
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal(err)
+            }
+            if err == nil && r.Results[0].Data != "done" {
+                t.Fatal("result is not polled")
+            }
+        })
+    }
+}
@@ -14,4 +14,37 @@ provider "m3" {
 	region = "COMPANY-OPENSTACK-3"
 	tenant = "EPMC-EOOS"
 	cloud = "cloud"
+}
+
+provider "m3" {
+	url = "https://ip:port/maestro/api/V3"
+	access_key = "access_key"
+	secret_key = "secret_key"
+	user_identifier = "user_identifier"
+	# path to a file or PEM content
+	ca_bundle = "/etc/ssl/internal-ca.pem"
+	client_certificate = "/etc/ssl/m3-client.pem"
+	client_key = "/etc/ssl/m3-client-key.pem"
+	tls_min_version = "1.2"
+}
+
+provider "m3" {
+	url = "http://ip:port/maestro/api/V3"
+	access_key = "access_key"
+	secret_key = "secret_key"
+	user_identifier = "user_identifier"
+	# tags of resources take precedence over default tags with the same keys
+	default_tags {
+		tags = {
+			team = "platform"
+			cost-center = "42"
+		}
+	}
+}
+
+# credentials are read from the "prod" profile of ~/.m3/credentials
+provider "m3" {
+	profile = "prod"
+	region = "COMPANY-OPENSTACK-3"
+	tenant = "EPMC-EOOS"
 }
\ No newline at end of file
@@ -0,0 +1,4 @@
+# Image is imported by tenant, region and image ID.
+# Source instance of the image is not known after import, so the next apply saves the configured one
+# in state by in-place update without replacing the image.
+terraform import m3_image.example EPMC-EOOS/COMPANY-OPENSTACK-3/ami-0123456789abcdef0
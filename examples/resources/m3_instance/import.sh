@@ -0,0 +1,3 @@
+# Instance is imported by tenant, region and instance ID.
+# Image is set to the image ID, use it in configuration or ignore changes of image.
+terraform import m3_instance.example EPMC-EOOS/COMPANY-OPENSTACK-3/i-0123456789abcdef0
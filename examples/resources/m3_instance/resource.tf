@@ -194,4 +194,56 @@ resource "m3_instance" "my-server" {
   #  max 720
   terminate_after = 6
   lock_termination = true
-}
\ No newline at end of file
+}
+
+resource "m3_instance" "my-server" {
+  image = data.m3_data_image.dim.id
+  name  = "test"
+  shape = "MINI"
+  key = "sshkey"
+  #  time in RFC3339 format, it is changed without recreation of the instance
+  stop_after = "2030-01-02T15:04:05Z"
+}
+
+resource "m3_instance" "my-server" {
+  image = data.m3_data_image.dim.id
+  name  = "test"
+  shape = "MINI"
+  key = "sshkey"
+  #  placement block must match the cloud of the provider
+  aws {
+    subnet_id          = "subnet-0123456789abcdef0"
+    security_group_ids = ["sg-0123456789abcdef0"]
+  }
+}
+
+resource "m3_instance" "my-server" {
+  image = data.m3_data_image.dim.id
+  name  = "test"
+  shape = "MINI"
+  key = "sshkey"
+  #  volumes are created and attached with the instance, size can be increased in place
+  volume {
+    name       = "data"
+    size_in_gb = 10
+  }
+  volume {
+    name       = "logs"
+    size_in_gb = 5
+  }
+}
+
+resource "m3_script" "init" {
+  name      = "init_server"
+  extension = ".sh"
+  content   = "#!/bin/sh\necho hello"
+}
+
+resource "m3_instance" "my-server" {
+  image = data.m3_data_image.dim.id
+  name  = "test"
+  shape = "MINI"
+  key = "sshkey"
+  #  the uploaded script is run when the instance boots, use user_data for raw data instead
+  startup_script = m3_script.init.id
+}
@@ -0,0 +1,5 @@
+# Keypair registered for all tenants is imported by its name
+terraform import m3_keypair.example keypair_name
+
+# Keypair of specific tenant is imported by tenant and name
+terraform import m3_keypair.example EPMC-EOOS/keypair_name
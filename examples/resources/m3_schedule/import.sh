@@ -0,0 +1,2 @@
+# Schedule is imported by tenant, region, cloud and schedule name
+terraform import m3_schedule.example EPMC-EOOS/COMPANY-OPENSTACK-3/OPEN_STACK/schedule_name
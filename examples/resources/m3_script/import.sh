@@ -0,0 +1,2 @@
+# Script is imported by tenant, region, cloud and file name which consists of script name and extension
+terraform import m3_script.example EPMC-EOOS/COMPANY-OPENSTACK-3/OPEN_STACK/script_name.sh
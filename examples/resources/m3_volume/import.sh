@@ -0,0 +1,5 @@
+# Volume is imported by tenant, region and volume ID
+terraform import m3_volume.example EPMC-EOOS/COMPANY-OPENSTACK-3/vol-0123456789abcdef0
+
+# Attached volume is imported with ID of the instance
+terraform import m3_volume.example EPMC-EOOS/COMPANY-OPENSTACK-3/i-0123456789abcdef0/vol-0123456789abcdef0
@@ -1,6 +1,7 @@
 package provider
 
 import (
+    "context"
     "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -11,7 +12,7 @@ import (
 func dataChef() *schema.Resource {
 
     return &schema.Resource{
-        Read:        DataChefCreate,
+        ReadContext: withDiagnostics(DataChefCreate),
         Description: "The Data Chef resource is used for specifying chef profiler for instances.",
         Schema: map[string]*schema.Schema{
             "tenant": {
@@ -42,7 +43,7 @@ func dataChef() *schema.Resource {
     }
 }
 
-func DataChefCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func DataChefCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer DataChefError.WrapP(&err)
 
@@ -62,7 +63,7 @@ func DataChefCreate(d *schema.ResourceData, meta interface{}) (err error) {
         Region:     region,
     }
 
-    chefs, err := m.Service.DataChefGetList(opts)
+    chefs, err := m.Service.DataChefGetList(ctx, opts)
     if err != nil {
         return err
     }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,7 +15,7 @@ import (
 func dataImage() *schema.Resource {
 
     return &schema.Resource{
-        Read:        DataImageCreate,
+        ReadContext: withDiagnostics(DataImageCreate),
         Description: "The Data Image resource is used for specifying images used for creating new images.",
         Schema: map[string]*schema.Schema{
             "tenant": {
@@ -70,7 +71,7 @@ func dataImage() *schema.Resource {
     }
 }
 
-func DataImageCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func DataImageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer DataImageError.WrapP(&err)
 
@@ -89,7 +90,7 @@ func DataImageCreate(d *schema.ResourceData, meta interface{}) (err error) {
         Region:     region,
     }
 
-    images, err := m.Service.DataImageGetList(opts)
+    images, err := m.Service.DataImageGetList(ctx, opts)
     if err != nil {
         return err
     }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/google/uuid"
@@ -12,7 +13,7 @@ import (
 func dataPlacementParams() *schema.Resource {
 
     return &schema.Resource{
-        Read:        DataParamsCreate,
+        ReadContext: withDiagnostics(DataParamsCreate),
         Description: "The Data placement params resource is used for describing native ids for vm placement.",
         Schema: map[string]*schema.Schema{
             "tenant": {
@@ -115,7 +116,7 @@ func processDataItem(item *service.DataItem, d *schema.ResourceData, dataMap map
     return nil
 }
 
-func DataParamsCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func DataParamsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer DataPlacementParamsError.WrapP(&err)
 
@@ -137,7 +138,7 @@ func DataParamsCreate(d *schema.ResourceData, meta interface{}) (err error) {
         Simplify: true,
     }
 
-    placementData, err := m.Service.DataPlacementGetList(opts)
+    placementData, err := m.Service.DataPlacementGetList(ctx, opts)
     if err != nil {
         return err
     }
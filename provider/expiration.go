@@ -0,0 +1,71 @@
+package provider
+
+import (
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "strconv"
+    "time"
+)
+
+// maxExpirationHours limits delayed stop and terminate given in hours after creation of instance
+const maxExpirationHours = 720
+
+// expirationHours returns number of hours of delayed action, ok is false if the action is given by time
+func expirationHours(value string) (hours int, ok bool) {
+    if value == "" {
+        return 0, true
+    }
+    hours, err := strconv.Atoi(value)
+    return hours, err == nil
+}
+
+// expirationTime returns time of delayed action which is given either in hours after creation or in RFC3339 format,
+// zero time means that there is no delayed action
+func expirationTime(value string, created time.Time) (time.Time, error) {
+    if hours, ok := expirationHours(value); ok {
+        if hours == 0 {
+            return time.Time{}, nil
+        }
+        return created.Add(time.Duration(hours) * time.Hour), nil
+    }
+    return time.Parse(time.RFC3339, value)
+}
+
+// formatExpirationTime formats time of delayed action for Maestro3, zero time clears the action
+func formatExpirationTime(at time.Time) string {
+    if at.IsZero() {
+        return ""
+    }
+    return at.UTC().Format(time.RFC3339)
+}
+
+// validateExpiration checks that delayed action is given either in hours after creation or in RFC3339 format
+func validateExpiration(v interface{}, k string) ([]string, []error) {
+    value := v.(string)
+    if hours, ok := expirationHours(value); ok {
+        if hours < 0 || hours > maxExpirationHours {
+            return nil, []error{fmt.Errorf("expected %s to be in the range (0 - %d) hours, got %d", k, maxExpirationHours, hours)}
+        }
+        return nil, nil
+    }
+    if _, err := time.Parse(time.RFC3339, value); err != nil {
+        return nil, []error{fmt.Errorf("expected %s to be a number of hours or a time in RFC3339 format, got %s", k, value)}
+    }
+    return nil, nil
+}
+
+// suppressEmptyExpiration suppresses difference between zero hours and absent delayed action
+func suppressEmptyExpiration(k, old, new string, d *schema.ResourceData) bool {
+    empty := func(value string) bool {
+        return value == "" || value == "0"
+    }
+    return empty(old) && empty(new)
+}
+
+// instanceCreated returns creation time of instance, current time is used if it is not known yet
+func instanceCreated(created string) time.Time {
+    if parsed, err := time.Parse(time.RFC3339, created); err == nil {
+        return parsed
+    }
+    return time.Now()
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+    "testing"
+    "time"
+)
+
+func TestExpirationTime(t *testing.T) {
+    created := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+    testTable := []struct {
+        Value   string
+        Want    time.Time
+        WantErr bool
+    }{
+        {Value: "", Want: time.Time{}},
+        {Value: "0", Want: time.Time{}},
+        {Value: "24", Want: created.Add(24 * time.Hour)},
+        {Value: "2030-02-01T00:00:00+02:00", Want: time.Date(2030, 1, 31, 22, 0, 0, 0, time.UTC)},
+        {Value: "tomorrow", WantErr: true},
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Value, func(t *testing.T) {
+            got, err := expirationTime(testCase.Value, created)
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatalf("unexpected error '%v'", err)
+            }
+            if !got.Equal(testCase.Want) {
+                t.Fatalf("got %s instead of %s", got, testCase.Want)
+            }
+        })
+    }
+}
+
+func TestValidateExpiration(t *testing.T) {
+    testTable := map[string]bool{
+        "":                     true,
+        "720":                  true,
+        "721":                  false,
+        "-1":                   false,
+        "2030-01-02T15:04:05Z": true,
+        "2030-01-02 15:04:05":  false,
+    }
+
+    for value, valid := range testTable {
+        if _, errs := validateExpiration(value, "stop_after"); (len(errs) == 0) != valid {
+            t.Errorf("validation of '%s' returned %v", value, errs)
+        }
+    }
+}
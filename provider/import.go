@@ -0,0 +1,74 @@
+package provider
+
+import (
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "strings"
+)
+
+// importIDSeparator separates parts of composite IDs used in terraform import
+const importIDSeparator = "/"
+
+// parseImportID matches ID with one of formats like "tenant/region/instance_id" by number of parts
+// and returns parts by their names
+func parseImportID(id string, formats ...string) (map[string]string, error) {
+    values := strings.Split(id, importIDSeparator)
+    for _, format := range formats {
+        names := strings.Split(format, importIDSeparator)
+        if len(names) != len(values) {
+            continue
+        }
+
+        parts := make(map[string]string, len(names))
+        for i, name := range names {
+            if values[i] == "" {
+                return nil, fmt.Errorf("%s is empty in ID '%s', expected format: %s", name, id, format)
+            }
+            parts[name] = values[i]
+        }
+        return parts, nil
+    }
+    return nil, fmt.Errorf("unexpected format of ID '%s', expected: %s", id, strings.Join(formats, " or "))
+}
+
+// importState sets default values of resource schema, because they are not applied to imported resources,
+// then sets parts of import ID which are attributes of resource and resource ID
+func importState(d *schema.ResourceData, resourceSchema map[string]*schema.Schema, id string, parts map[string]string) ([]*schema.ResourceData, error) {
+    for name, attribute := range resourceSchema {
+        if attribute.Default == nil {
+            continue
+        }
+        if err := d.Set(name, attribute.Default); err != nil {
+            return nil, err
+        }
+    }
+    for name, value := range parts {
+        if _, ok := resourceSchema[name]; !ok {
+            continue
+        }
+        if err := d.Set(name, value); err != nil {
+            return nil, err
+        }
+    }
+    d.SetId(id)
+    return []*schema.ResourceData{d}, nil
+}
+
+// setAttributes sets values of several attributes
+func setAttributes(d *schema.ResourceData, values map[string]interface{}) error {
+    for name, value := range values {
+        if err := d.Set(name, value); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// keepCase returns current value of attribute if it differs from remote value only by case,
+// so values validated case-insensitively do not cause diff
+func keepCase(d *schema.ResourceData, name string, value string) string {
+    if current, ok := d.Get(name).(string); ok && strings.EqualFold(current, value) {
+        return current
+    }
+    return value
+}
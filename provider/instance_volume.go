@@ -0,0 +1,311 @@
+package provider
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+    "terraform-provider-m3/service"
+)
+
+// instanceVolume is configured volume block of instance, volumes are matched to the blocks by names
+type instanceVolume struct {
+    Name string
+    Size int
+}
+
+// instanceVolumeSchema returns schema of volume blocks of instance
+func instanceVolumeSchema() *schema.Schema {
+    return &schema.Schema{
+        Type:     schema.TypeList,
+        Optional: true,
+        Description: "Data volumes which are created and attached to every instance launched by the resource. " +
+            "Volumes are matched by names, so added volumes are created and attached, removed ones are detached and deleted " +
+            "and size of volumes is increased without recreation of the instance. Volumes are deleted after their instances are terminated. " +
+            "A volume block can not be renamed, because the volume with the old name would be deleted with its data: " +
+            "remove the block and add the new one by separate applies instead.",
+        Elem: &schema.Resource{
+            Schema: map[string]*schema.Schema{
+                "name": {
+                    Type:        schema.TypeString,
+                    Required:    true,
+                    Description: "The volume name, it is unique among volumes of the instance.",
+                },
+                "size_in_gb": {
+                    Type:         schema.TypeInt,
+                    Required:     true,
+                    ValidateFunc: validation.IntAtLeast(1),
+                    Description:  "The size of the volume in GB, it can only be increased.",
+                },
+                "id": {
+                    Type:        schema.TypeString,
+                    Computed:    true,
+                    Description: "The ID of the volume attached to the instance of the resource, IDs of volumes of every instance are in `volume_ids` of `instances`.",
+                },
+            },
+        },
+    }
+}
+
+// instanceVolumes converts value of volume blocks
+func instanceVolumes(value interface{}) []instanceVolume {
+    blocks := value.([]interface{})
+    volumes := make([]instanceVolume, 0, len(blocks))
+    for _, block := range blocks {
+        if block == nil {
+            continue
+        }
+        attributes := block.(map[string]interface{})
+        volumes = append(volumes, instanceVolume{
+            Name: attributes["name"].(string),
+            Size: attributes["size_in_gb"].(int),
+        })
+    }
+    return volumes
+}
+
+// resourceInstanceCheckVolumes checks that names of volumes are unique, size of existing volumes is not decreased
+// and existing volume blocks are not renamed, because volumes are matched by names and renamed volume would be deleted
+func resourceInstanceCheckVolumes(d *schema.ResourceDiff) error {
+    if !d.NewValueKnown("volume") {
+        return nil
+    }
+    before, after := d.GetChange("volume")
+    existing, configured := instanceVolumes(before), instanceVolumes(after)
+    sizes := make(map[string]int)
+    for _, volume := range existing {
+        sizes[volume.Name] = volume.Size
+    }
+    names := make(map[string]bool)
+    for _, volume := range configured {
+        // attributes which are not known yet are empty
+        if volume.Name == "" {
+            continue
+        }
+        if names[volume.Name] {
+            return fmt.Errorf("volume %s is configured more than once, names of volumes must be unique", volume.Name)
+        }
+        names[volume.Name] = true
+        if size, ok := sizes[volume.Name]; ok && volume.Size != 0 && volume.Size < size {
+            return fmt.Errorf("size of volume %s can not be decreased from %d to %d GB", volume.Name, size, volume.Size)
+        }
+    }
+
+    // block which gets a new name in place of a removed one is renamed
+    for i := 0; i < len(existing) && i < len(configured); i++ {
+        old, name := existing[i].Name, configured[i].Name
+        if name == "" || name == old || names[old] {
+            continue
+        }
+        if _, ok := sizes[name]; !ok {
+            return fmt.Errorf("volume %s can not be renamed to %s, the volume would be deleted with its data, "+
+                "remove the block and add the new one by separate applies instead", old, name)
+        }
+    }
+    return nil
+}
+
+// resourceInstanceAttachedVolumes returns data volumes attached to instance by their names, system volumes are skipped
+func resourceInstanceAttachedVolumes(ctx context.Context, m *Meta, params *service.DefaultRequestParams, instance *service.Instance) (map[string]service.Volume, error) {
+    attached := make(map[string]service.Volume)
+    if len(instance.VolumesIds) == 0 {
+        return attached, nil
+    }
+    volumes, err := m.Service.VolumeServicer.List(ctx, &service.VolumeDescribeRequest{
+        DefaultRequestParams: params,
+        VolumeIds:            instance.VolumesIds,
+        InstanceId:           instance.InstanceID,
+    })
+    if err != nil {
+        return nil, err
+    }
+    for _, volume := range volumes {
+        if !volume.System {
+            attached[volume.Name] = volume
+        }
+    }
+    return attached, nil
+}
+
+// resourceInstanceReadVolumes returns volume blocks of state and IDs of volumes of the blocks attached to every instance
+// by volume names. Blocks of volumes which are not attached to some instance anymore are dropped, so the volumes are created
+// again by the next apply. IDs and sizes of blocks are taken from the first instance which is the instance of the resource
+func resourceInstanceReadVolumes(ctx context.Context, m *Meta, d *schema.ResourceData, params *service.DefaultRequestParams, instances []*service.Instance) ([]interface{}, []map[string]interface{}, error) {
+    configured := instanceVolumes(d.Get("volume"))
+    blocks := make([]interface{}, 0, len(configured))
+    ids := make([]map[string]interface{}, 0, len(instances))
+    attached := make([]map[string]service.Volume, 0, len(instances))
+    for _, instance := range instances {
+        instanceIDs := make(map[string]interface{}, len(configured))
+        ids = append(ids, instanceIDs)
+        if len(configured) == 0 {
+            continue
+        }
+        volumes, err := resourceInstanceAttachedVolumes(ctx, m, params, instance)
+        if err != nil {
+            return nil, nil, err
+        }
+        attached = append(attached, volumes)
+        for _, volume := range configured {
+            if v, ok := volumes[volume.Name]; ok {
+                instanceIDs[volume.Name] = v.VolumeID
+            }
+        }
+    }
+    if len(attached) == 0 {
+        return blocks, ids, nil
+    }
+
+    for _, volume := range configured {
+        v, ok := attached[0][volume.Name]
+        for _, volumes := range attached[1:] {
+            if _, found := volumes[volume.Name]; !found {
+                ok = false
+            }
+        }
+        if ok {
+            blocks = append(blocks, map[string]interface{}{
+                "name":       v.Name,
+                "size_in_gb": v.SizeLabel,
+                "id":         v.VolumeID,
+            })
+        }
+    }
+    return blocks, ids, nil
+}
+
+// resourceInstanceApplyVolumes makes volumes of instance match volume blocks: removed volumes are deleted,
+// volumes are resized if size is increased and missing volumes are created and attached
+func resourceInstanceApplyVolumes(ctx context.Context, m *Meta, d *schema.ResourceData, params *service.DefaultRequestParams, id string) error {
+    instance, err := m.Service.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+        DefaultRequestParams: params,
+        InstanceIds:          []string{id},
+    })
+    if err != nil {
+        return err
+    }
+    attached, err := resourceInstanceAttachedVolumes(ctx, m, params, instance)
+    if err != nil {
+        return err
+    }
+
+    before, after := d.GetChange("volume")
+    configured := instanceVolumes(after)
+    names := make(map[string]bool, len(configured))
+    for _, volume := range configured {
+        names[volume.Name] = true
+    }
+    // only volumes which were configured are deleted, volumes attached by other means are kept
+    for _, volume := range instanceVolumes(before) {
+        v, ok := attached[volume.Name]
+        if !ok || names[volume.Name] {
+            continue
+        }
+        if err = resourceInstanceDeleteVolume(ctx, m, params, id, v.VolumeID); err != nil {
+            return err
+        }
+    }
+
+    for _, volume := range configured {
+        v, ok := attached[volume.Name]
+        if ok {
+            if volume.Size <= v.SizeLabel {
+                continue
+            }
+            m.Log.Info(fmt.Sprintf("Resizing volume %s of instance %s to %d GB", v.VolumeID, id, volume.Size))
+            err = m.Service.VolumeServicer.Resize(ctx, &service.VolumeResizeRequest{
+                DefaultRequestParams: params,
+                VolumeID:             v.VolumeID,
+                SizeInGB:             volume.Size,
+            })
+            if err != nil {
+                return err
+            }
+            if err = volumeWaitResized(ctx, m, params, v.VolumeID, id, volume.Size); err != nil {
+                return err
+            }
+            continue
+        }
+
+        created, err := m.Service.VolumeServicer.CreateAndAttach(ctx, &service.VolumeCreateAndAttachRequest{
+            DefaultRequestParams: params,
+            VolumeName:           volume.Name,
+            SizeInGB:             volume.Size,
+            InstanceId:           id,
+        })
+        if err != nil {
+            return err
+        }
+        m.Log.Info(fmt.Sprintf("Creating volume %s of instance %s", created.VolumeID, id))
+        if err = volumeWaitState(ctx, m, params, created.VolumeID, id, service.InUseState); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// resourceInstanceDeleteVolume detaches and deletes volume of instance and waits until it is not found
+func resourceInstanceDeleteVolume(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, volumeID string) error {
+    err := volumeDetach(ctx, m, params, volumeID, id)
+    if err != nil && !errors.Is(err, service.ErrNotFound) {
+        return err
+    }
+    return resourceInstanceRemoveVolume(ctx, m, params, id, volumeID)
+}
+
+// resourceInstanceRemoveVolume deletes detached volume of instance and waits until it is not found
+func resourceInstanceRemoveVolume(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, volumeID string) error {
+    m.Log.Info(fmt.Sprintf("Deleting volume %s of instance %s", volumeID, id))
+    err := m.Service.VolumeServicer.Delete(ctx, &service.VolumeDeleteRequest{
+        DefaultRequestParams: params,
+        VolumeID:             volumeID,
+    })
+    if err != nil && !errors.Is(err, service.ErrNotFound) {
+        return err
+    }
+
+    _, err = wait{
+        Action: deletedWaitAction(func() (interface{}, error) {
+            return m.Service.VolumeServicer.Describe(ctx, &service.VolumeDescribeRequest{
+                DefaultRequestParams: params,
+                VolumeIds:            []string{volumeID},
+            })
+        }),
+        CompareFn: notFoundWaitCompareFunc(),
+    }.Wait(ctx)
+    if err != nil {
+        return fmt.Errorf("volume %s is not confirmed to be deleted: %w", volumeID, err)
+    }
+    return nil
+}
+
+// resourceInstanceConfiguredVolumes returns IDs of volumes of volume blocks which are attached to instance,
+// they are collected before termination, because terminated instance does not report its volumes
+func resourceInstanceConfiguredVolumes(ctx context.Context, m *Meta, d *schema.ResourceData, params *service.DefaultRequestParams, id string) ([]string, error) {
+    configured := instanceVolumes(d.Get("volume"))
+    if len(configured) == 0 {
+        return nil, nil
+    }
+    instance, err := m.Service.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+        DefaultRequestParams: params,
+        InstanceIds:          []string{id},
+    })
+    if errors.Is(err, service.ErrNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    attached, err := resourceInstanceAttachedVolumes(ctx, m, params, instance)
+    if err != nil {
+        return nil, err
+    }
+    ids := make([]string, 0, len(configured))
+    for _, volume := range configured {
+        if v, ok := attached[volume.Name]; ok {
+            ids = append(ids, v.VolumeID)
+        }
+    }
+    return ids, nil
+}
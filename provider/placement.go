@@ -0,0 +1,147 @@
+package provider
+
+import (
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "sort"
+    "strings"
+    "terraform-provider-m3/utils"
+)
+
+// placement describes typed placement block of instance
+type placement struct {
+    // Clouds are clouds where the block is allowed
+    Clouds []string
+    // Keys maps attributes of the block to keys of additional data of run request
+    Keys map[string]string
+}
+
+// placements contains typed placement blocks of instance by their names
+var placements = map[string]placement{
+    "aws": {
+        Clouds: []string{"AWS"},
+        Keys: map[string]string{
+            "subnet_id":          "subnetId",
+            "security_group_ids": "securityGroupIds",
+            "availability_zone":  "availabilityZone",
+        },
+    },
+    "azure": {
+        Clouds: []string{"AZURE"},
+        Keys: map[string]string{
+            "resource_group": "resourceGroup",
+            "vnet":           "vnet",
+            "subnet":         "subnet",
+        },
+    },
+    "google": {
+        Clouds: []string{"GOOGLE"},
+        Keys: map[string]string{
+            "zone":       "zone",
+            "network":    "network",
+            "subnetwork": "subnetwork",
+        },
+    },
+    // native IDs of vSphere placement are described by m3_data_placement_params
+    "vsphere": {
+        Clouds: []string{"VSPHERE", "VMWARE"},
+        Keys: map[string]string{
+            "cluster":       "clusterId",
+            "datastore":     "datastoreId",
+            "folder":        "folderId",
+            "resource_pool": "resPoolId",
+            "host":          "hostId",
+        },
+    },
+}
+
+// placementNames returns sorted names of placement blocks
+func placementNames() []string {
+    names := make([]string, 0, len(placements))
+    for name := range placements {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// placementAttribute returns schema of string attribute of placement block
+func placementAttribute(description string) *schema.Schema {
+    return &schema.Schema{
+        Type:        schema.TypeString,
+        Optional:    true,
+        ForceNew:    true,
+        Description: description,
+    }
+}
+
+// placementSchema returns schema of placement block with attributes, only one placement block can be configured
+func placementSchema(name, description string, attributes map[string]*schema.Schema) *schema.Schema {
+    conflicts := make([]string, 0, len(placements)-1)
+    for _, other := range placementNames() {
+        if other != name {
+            conflicts = append(conflicts, other)
+        }
+    }
+    return &schema.Schema{
+        Type:          schema.TypeList,
+        Optional:      true,
+        ForceNew:      true,
+        MaxItems:      1,
+        ConflictsWith: conflicts,
+        Description: fmt.Sprintf("%s Allowed for clouds: [%s].",
+            description, strings.Join(placements[name].Clouds, ", ")),
+        Elem: &schema.Resource{
+            Schema: attributes,
+        },
+    }
+}
+
+// placementAdditionalData returns additional data of run request with configured placement block serialized into it,
+// attributes of placement block take precedence over the same keys of additional_data
+func placementAdditionalData(d utils.Getter) map[string]interface{} {
+    data := make(map[string]interface{})
+    for key, value := range d.Get("additional_data").(map[string]interface{}) {
+        data[key] = value
+    }
+    for name, p := range placements {
+        blocks := d.Get(name).([]interface{})
+        if len(blocks) == 0 || blocks[0] == nil {
+            continue
+        }
+        for attribute, value := range blocks[0].(map[string]interface{}) {
+            if list, ok := value.([]interface{}); ok {
+                values := make([]string, 0, len(list))
+                for _, v := range list {
+                    values = append(values, v.(string))
+                }
+                value = values
+                if len(values) == 0 {
+                    continue
+                }
+            }
+            if value == "" {
+                continue
+            }
+            data[p.Keys[attribute]] = value
+        }
+    }
+    return data
+}
+
+// checkPlacementCloud checks that configured placement block is allowed for cloud
+func checkPlacementCloud(d utils.Getter, cloud string) error {
+    for _, name := range placementNames() {
+        if len(d.Get(name).([]interface{})) == 0 {
+            continue
+        }
+        allowed := placements[name].Clouds
+        for _, c := range allowed {
+            if strings.EqualFold(c, cloud) {
+                return nil
+            }
+        }
+        return fmt.Errorf("%s block is not allowed for cloud %s, allowed clouds: [%s]", name, cloud, strings.Join(allowed, ", "))
+    }
+    return nil
+}
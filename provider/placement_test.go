@@ -0,0 +1,56 @@
+package provider
+
+import (
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "reflect"
+    "testing"
+)
+
+func TestPlacementAdditionalData(t *testing.T) {
+    d := schema.TestResourceDataRaw(t, resourceInstance().Schema, map[string]interface{}{
+        "additional_data": map[string]interface{}{
+            "subnetId": "subnet-1",
+            "role":     "web",
+        },
+        "aws": []interface{}{
+            map[string]interface{}{
+                "subnet_id":          "subnet-2",
+                "security_group_ids": []interface{}{"sg-1", "sg-2"},
+            },
+        },
+    })
+
+    want := map[string]interface{}{
+        "subnetId":         "subnet-2",
+        "securityGroupIds": []string{"sg-1", "sg-2"},
+        "role":             "web",
+    }
+    if got := placementAdditionalData(d); !reflect.DeepEqual(got, want) {
+        t.Fatalf("got %v instead of %v", got, want)
+    }
+}
+
+func TestCheckPlacementCloud(t *testing.T) {
+    testTable := []struct {
+        Block   string
+        Cloud   string
+        WantErr bool
+    }{
+        {Block: "aws", Cloud: "AWS"},
+        {Block: "vsphere", Cloud: "vmware"},
+        {Block: "azure", Cloud: "AWS", WantErr: true},
+        {Block: "google", Cloud: "OPEN_STACK", WantErr: true},
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Block+"_"+testCase.Cloud, func(t *testing.T) {
+            d := schema.TestResourceDataRaw(t, resourceInstance().Schema, map[string]interface{}{
+                testCase.Block: []interface{}{map[string]interface{}{}},
+            })
+            err := checkPlacementCloud(d, testCase.Cloud)
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatalf("unexpected error '%v'", err)
+            }
+        })
+    }
+}
@@ -1,11 +1,15 @@
 package provider
 
 import (
+    "context"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
     "github.com/zeebo/errs"
     "terraform-provider-m3/client"
     "terraform-provider-m3/logger"
     "terraform-provider-m3/service"
+    "time"
 )
 
 var (
@@ -28,6 +32,8 @@ type Meta struct {
     Service *service.Service
     Config  *client.Config
     Log     logger.Log
+    // DefaultTags are merged into tags of every taggable resource
+    DefaultTags map[string]interface{}
 }
 
 func newMeta(s *service.Service, conf *client.Config, log logger.Log) *Meta {
@@ -47,12 +53,13 @@ func Provider() *schema.Provider {
             "access_key": {
                 Type:        schema.TypeString,
                 Optional:    true,
-                Description: "The access key.",
+                Description: "The access key. It is set together with `secret_key`, both keys are taken from the same credential source.",
             },
             "secret_key": {
                 Type:        schema.TypeString,
                 Optional:    true,
-                Description: "The secret key.",
+                Sensitive:   true,
+                Description: "The secret key. It is set together with `access_key`.",
             },
             "user_identifier": {
                 Type:        schema.TypeString,
@@ -74,6 +81,116 @@ func Provider() *schema.Provider {
                 Optional:    true,
                 Description: "The cloud. \nAllowed values: [AWS, AZURE, GOOGLE, NUTANIX, OPEN_STACK, VSPHERE, VMWARE, YANDEX].",
             },
+            "profile": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_PROFILE", ""),
+                Description: "The profile of shared credentials file. The default value is `default`.",
+            },
+            "shared_credentials_file": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_SHARED_CREDENTIALS_FILE", ""),
+                Description: "Path to shared credentials file. The default value is `~/.m3/credentials`.",
+            },
+            "credential_process": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_CREDENTIAL_PROCESS", ""),
+                Description: "External command which prints credentials as JSON object with the same keys as provider arguments.",
+            },
+            "ca_bundle": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_CA_BUNDLE", ""),
+                Description: "Path to a file or PEM encoded content of CA certificates used to verify Maestro3 API certificate.",
+            },
+            "client_certificate": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_CLIENT_CERTIFICATE", ""),
+                Description: "Path to a file or PEM encoded content of client certificate for mutual TLS.",
+            },
+            "client_key": {
+                Type:        schema.TypeString,
+                Optional:    true,
+                Sensitive:   true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_CLIENT_KEY", ""),
+                Description: "Path to a file or PEM encoded content of client private key for mutual TLS.",
+            },
+            "tls_min_version": {
+                Type:         schema.TypeString,
+                Optional:     true,
+                DefaultFunc:  schema.EnvDefaultFunc("TERRAFORM_M3_TLS_MIN_VERSION", "1.2"),
+                ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+                Description:  "Minimum TLS version. \nAllowed values: [1.0, 1.1, 1.2, 1.3].",
+            },
+            "insecure": {
+                Type:        schema.TypeBool,
+                Optional:    true,
+                DefaultFunc: schema.EnvDefaultFunc("TERRAFORM_M3_INSECURE", false),
+                Description: "Skip verification of Maestro3 API certificate. Do not use in production.",
+            },
+            "max_retries": {
+                Type:         schema.TypeInt,
+                Optional:     true,
+                Default:      3,
+                ValidateFunc: validation.IntAtLeast(0),
+                Description:  "Maximum number of retries of requests failed with network errors, throttling or server errors. Requests creating resources are retried only if they were not processed by server.",
+            },
+            "retry_max_wait": {
+                Type:         schema.TypeInt,
+                Optional:     true,
+                Default:      30,
+                ValidateFunc: validation.IntAtLeast(1),
+                Description:  "Maximum wait between retries, in seconds. It limits the delay requested by server in Retry-After header as well.",
+            },
+            "log_sensitive_fields": {
+                Type:        schema.TypeList,
+                Optional:    true,
+                Elem:        &schema.Schema{Type: schema.TypeString},
+                Description: "JSON fields of requests and responses which values are masked in logs. Public keys, script contents and private key parts are always masked.",
+            },
+            "default_tags": {
+                Type:        schema.TypeList,
+                Optional:    true,
+                MaxItems:    1,
+                Description: "Tags applied to every taggable resource. Tags of resource take precedence over default tags with the same keys.",
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "tags": {
+                            Type:        schema.TypeMap,
+                            Optional:    true,
+                            Description: "Key value pairs merged into tags of resources.",
+                            Elem: &schema.Schema{
+                                Type: schema.TypeString,
+                            },
+                        },
+                    },
+                },
+            },
+            "async": {
+                Type:     schema.TypeBool,
+                Optional: true,
+                Default:  false,
+                Description: "Send requests in async mode, results of long-running actions are polled instead of waiting on open connection. " +
+                    "Experimental: results are polled from the `/results` endpoint of `url`, which is not part of the documented Maestro3 API, " +
+                    "so enable it only for servers which provide the endpoint.",
+            },
+            "async_poll_interval": {
+                Type:         schema.TypeInt,
+                Optional:     true,
+                Default:      5,
+                ValidateFunc: validation.IntAtLeast(1),
+                Description:  "Interval between polls of async request results, in seconds.",
+            },
+            "async_timeout": {
+                Type:         schema.TypeInt,
+                Optional:     true,
+                Default:      3600,
+                ValidateFunc: validation.IntAtLeast(1),
+                Description:  "Maximum time to wait for async request results, in seconds. It limits every poll instead of the default timeout of requests.",
+            },
         },
         ResourcesMap: map[string]*schema.Resource{
             "m3_instance": resourceInstance(),
@@ -88,22 +205,75 @@ func Provider() *schema.Provider {
             "m3_data_chef":  dataChef(),
             "m3_data_placement_params": dataPlacementParams(),
         },
-        ConfigureFunc: providerConfigure,
+        ConfigureContextFunc: providerConfigure,
+    }
+}
+
+// withDiagnostics adapts resource function which returns error to context aware function of schema.Resource
+func withDiagnostics(f func(context.Context, *schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+    return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+        return diag.FromErr(f(ctx, d, meta))
     }
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-    conf := client.NewConfig(
-        d.Get("url").(string),
-        d.Get("user_identifier").(string),
-        d.Get("access_key").(string),
-        d.Get("secret_key").(string),
-        d.Get("tenant").(string),
-        d.Get("region").(string),
-        d.Get("cloud").(string),
-    )
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+    tlsConfig, err := client.NewTLSConfig(&client.TLSOptions{
+        CABundle:          d.Get("ca_bundle").(string),
+        ClientCertificate: d.Get("client_certificate").(string),
+        ClientKey:         d.Get("client_key").(string),
+        MinVersion:        d.Get("tls_min_version").(string),
+        Insecure:          d.Get("insecure").(bool),
+    })
+    if err != nil {
+        return nil, diag.FromErr(err)
+    }
+
+    chain := client.CredentialChain{
+        &client.StaticCredentials{Credentials: client.Credentials{
+            URL:            d.Get("url").(string),
+            AccessKey:      d.Get("access_key").(string),
+            SecretKey:      d.Get("secret_key").(string),
+            UserIdentifier: d.Get("user_identifier").(string),
+            TenantName:     d.Get("tenant").(string),
+            RegionName:     d.Get("region").(string),
+            Cloud:          d.Get("cloud").(string),
+        }},
+        &client.EnvCredentials{},
+        &client.SharedCredentialsFile{
+            Path:    d.Get("shared_credentials_file").(string),
+            Profile: d.Get("profile").(string),
+        },
+        &client.CredentialProcess{Command: d.Get("credential_process").(string)},
+    }
+    creds, err := chain.Retrieve(ctx)
+    if err != nil {
+        return nil, diag.Diagnostics{{
+            Severity: diag.Error,
+            Summary:  "Failed to resolve Maestro3 credentials",
+            Detail:   err.Error(),
+        }}
+    }
+
+    conf := client.NewConfig(creds, tlsConfig)
+    conf.Async = client.AsyncConfig{
+        Enabled:      d.Get("async").(bool),
+        PollInterval: time.Duration(d.Get("async_poll_interval").(int)) * time.Second,
+        Timeout:      time.Duration(d.Get("async_timeout").(int)) * time.Second,
+    }
+    conf.Retry = client.RetryPolicy{
+        MaxRetries:    d.Get("max_retries").(int),
+        MinWait:       time.Second,
+        MaxWait:       time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+        NonIdempotent: service.NonIdempotentMethods,
+    }
+    for _, field := range d.Get("log_sensitive_fields").([]interface{}) {
+        conf.SensitiveFields = append(conf.SensitiveFields, field.(string))
+    }
     c := client.NewClient(conf)
     s := service.NewService(c)
     m := newMeta(s, conf, logger.NewTFLog())
+    if blocks := d.Get("default_tags").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+        m.DefaultTags = blocks[0].(map[string]interface{})["tags"].(map[string]interface{})
+    }
     return m, nil
 }
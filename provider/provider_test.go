@@ -0,0 +1,104 @@
+package provider
+
+import (
+    "context"
+    "crypto/tls"
+    "errors"
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+    "strings"
+    "terraform-provider-m3/client"
+    "terraform-provider-m3/service"
+    "terraform-provider-m3/simulator"
+    "testing"
+)
+
+const (
+    testAccTenant = "ACC-TENANT"
+    testAccRegion = "ACC-REGION"
+    testAccCloud  = "AWS"
+)
+
+// testAccProviderFactories are used by acceptance tests, they run against the local simulator
+// of Maestro3 API, so TF_ACC=1 is the only requirement besides Terraform CLI
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+    "m3": func() (*schema.Provider, error) {
+        return Provider(), nil
+    },
+}
+
+func TestProvider(t *testing.T) {
+    if err := Provider().InternalValidate(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// testAccSimulator starts simulator of Maestro3 API which is closed at the end of the test,
+// default options of simulator may be changed by configure functions
+func testAccSimulator(t *testing.T, configure ...func(*simulator.Options)) *simulator.Server {
+    opts := simulator.Options{
+        AccessKey:      "acc-access-key",
+        SecretKey:      "0123456789abcdef0123456789abcdef",
+        UserIdentifier: "acc@example.com",
+        Cloud:          testAccCloud,
+    }
+    for _, f := range configure {
+        f(&opts)
+    }
+    server := simulator.New(opts)
+    t.Cleanup(server.Close)
+    return server
+}
+
+// testAccProviderConfig returns provider block pointing to simulator
+func testAccProviderConfig(server *simulator.Server) string {
+    creds := server.Credentials()
+    return fmt.Sprintf(`
+provider "m3" {
+	url             = %q
+	access_key      = %q
+	secret_key      = %q
+	user_identifier = %q
+	tenant          = %q
+	region          = %q
+	cloud           = %q
+}
+`, creds.URL, creds.AccessKey, creds.SecretKey, creds.UserIdentifier, testAccTenant, testAccRegion, testAccCloud)
+}
+
+// testAccService returns service which talks to simulator directly to check resources out of Terraform
+func testAccService(server *simulator.Server) *service.Service {
+    return service.NewService(client.NewClient(client.NewConfig(server.Credentials(), &tls.Config{})))
+}
+
+// testAccImportID returns function building composite import ID from given parts and ID of the resource
+func testAccImportID(resourceName string, parts ...string) resource.ImportStateIdFunc {
+    return func(s *terraform.State) (string, error) {
+        rs, ok := s.RootModule().Resources[resourceName]
+        if !ok {
+            return "", fmt.Errorf("resource %s is not found", resourceName)
+        }
+        return strings.Join(append(parts, rs.Primary.ID), importIDSeparator), nil
+    }
+}
+
+// testAccCheckDestroy checks that every resource of the type is not found by describe function
+func testAccCheckDestroy(resourceType string, describe func(ctx context.Context, rs *terraform.ResourceState) error) func(*terraform.State) error {
+    return func(s *terraform.State) error {
+        for _, rs := range s.RootModule().Resources {
+            if rs.Type != resourceType {
+                continue
+            }
+            err := describe(context.Background(), rs)
+            if err == nil {
+                return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+            }
+            if !errors.Is(err, service.ErrNotFound) {
+                return err
+            }
+        }
+        return nil
+    }
+}
@@ -1,29 +1,42 @@
 package provider
 
 import (
+    "context"
     "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "terraform-provider-m3/service"
     "terraform-provider-m3/utils"
+    "time"
 )
 
 func resourceImage() *schema.Resource {
     return &schema.Resource{
-        Create:      resourceImageCreate,
-        Read:        resourceImageRead,
-        Delete:      resourceImageDelete,
+        CreateContext: withDiagnostics(resourceImageCreate),
+        ReadContext:   withDiagnostics(resourceImageRead),
+        UpdateContext: withDiagnostics(resourceImageUpdate),
+        DeleteContext: withDiagnostics(resourceImageDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceImageImport,
+        },
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(40 * time.Minute),
+            Read:   schema.DefaultTimeout(5 * time.Minute),
+            Delete: schema.DefaultTimeout(20 * time.Minute),
+        },
         Description: "Creates an image based on an existing instance.",
         Schema: map[string]*schema.Schema{
             "tenant": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the tenant to which the source instance belongs.",
             },
             "region": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the region where the source instance is hosted.",
             },
@@ -36,8 +49,7 @@ func resourceImage() *schema.Resource {
             "source_instance_id": {
                 Type:        schema.TypeString,
                 Required:    true,
-                ForceNew:    true,
-                Description: "The ID of the instance used as a source for the image.",
+                Description: "The ID of the instance used as a source for the image. Changing it replaces the image, except the first apply after import which only saves it in state.",
             },
             "description": {
                 Type:        schema.TypeString,
@@ -46,10 +58,21 @@ func resourceImage() *schema.Resource {
                 Description: "The description for the image.",
             },
         },
+        CustomizeDiff: resourceImageCustomizeDiff,
     }
 }
 
-func resourceImageCreate(d *schema.ResourceData, meta interface{}) (err error) {
+// resourceImageCustomizeDiff replaces image when its source instance is changed. Maestro does not return source instance
+// of image, so it is empty after import and the configured value is saved by in-place update once
+func resourceImageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+    old, _ := d.GetChange("source_instance_id")
+    if d.Id() != "" && old.(string) != "" && d.HasChange("source_instance_id") {
+        return d.ForceNew("source_instance_id")
+    }
+    return nil
+}
+
+func resourceImageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceImageError.WrapP(&err)
     m := meta.(*Meta)
@@ -71,46 +94,43 @@ func resourceImageCreate(d *schema.ResourceData, meta interface{}) (err error) {
         DefaultRequestParams: defaultParams,
         InstanceID:           d.Get("source_instance_id").(string),
         ImageName:            d.Get("name").(string),
-        Description:          d.Get("description").(string),
+        Description: d.Get("description").(string),
     }
 
-    image, err := m.Service.ImageServicer.Create(opts)
+    image, err := m.Service.ImageServicer.Create(ctx, opts)
     if err != nil {
         return err
     }
 
-    w := wait{
-        Action: func() (interface{}, error) {
-            image, err := m.Service.ImageServicer.Describe(
+    // image is kept in state if it fails, so the resource is tainted and replaced by the next apply
+    d.SetId(image.ImageID)
+    w := stateWait{
+        Resource: "image " + d.Id(),
+        Pending:  []string{service.PendingImageState},
+        Target:   []string{service.AvailableImageState},
+        Failed:   []string{service.FailedImageState},
+        Refresh: func() (interface{}, string, string, error) {
+            image, err := m.Service.ImageServicer.Describe(ctx,
                 &service.ImageDescribeRequest{
                     DefaultRequestParams: defaultParams,
                     ImageIds:             []string{d.Id()},
                 })
             if err != nil {
-                return nil, err
+                return nil, "", "", err
             }
-            if image.State != service.AvailableImageState {
-                return nil, errors.New("image state: not available")
-            }
-            return image, nil
+            return image, image.State, image.StateReason, nil
         },
-        CompareFn: defaultWaitCompareFunc(),
     }
-    _, err = w.Wait()
+    _, err = w.Wait(ctx)
     if err != nil {
-        if err.Error() == "404" {
-            return errors.New("image not found")
-        }
-
-        return fmt.Errorf("error wait for state %s", err)
+        return fmt.Errorf("error wait for state: %s", err)
     }
-    d.SetId(image.ImageID)
 
     m.Log.Info(fmt.Sprintf("Image created ID: %s", d.Id()))
-    return resourceImageRead(d, meta)
+    return resourceImageRead(ctx, d, meta)
 }
 
-func resourceImageRead(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceImageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer ReadingError.WrapP(&err)
     defer ResourceImageError.WrapP(&err)
 
@@ -132,19 +152,44 @@ func resourceImageRead(d *schema.ResourceData, meta interface{}) (err error) {
         ImageIds: []string{d.Id()},
     }
 
-    _, err = m.Service.ImageServicer.Describe(opts)
+    image, err := m.Service.ImageServicer.Describe(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
-            return errors.New("image not found")
+        if errors.Is(err, service.ErrNotFound) {
+            m.Log.Info(fmt.Sprintf("Image %s not found", d.Id()))
+            d.SetId("")
+            return nil
         }
 
-        return fmt.Errorf("error wait for state %s", err)
+        return err
+    }
+
+    if err = setAttributes(d, map[string]interface{}{"tenant": tenant, "region": region}); err != nil {
+        return err
     }
+    if err = d.Set("name", image.Name); err != nil {
+        return err
+    }
+    return d.Set("description", image.Description)
+}
 
-    return nil
+// resourceImageUpdate saves source instance which is unknown after import, other arguments force replacement
+func resourceImageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
+    defer UpdatingError.WrapP(&err)
+    defer ResourceImageError.WrapP(&err)
+
+    return resourceImageRead(ctx, d, meta)
 }
 
-func resourceImageDelete(d *schema.ResourceData, meta interface{}) (err error) {
+// resourceImageImport imports image by ID in format tenant/region/image_id
+func resourceImageImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "tenant/region/image_id")
+    if err != nil {
+        return nil, err
+    }
+    return importState(d, resourceImage().Schema, parts["image_id"], parts)
+}
+
+func resourceImageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer DeletingError.WrapP(&err)
     defer ResourceImageError.WrapP(&err)
 
@@ -169,10 +214,10 @@ func resourceImageDelete(d *schema.ResourceData, meta interface{}) (err error) {
         DefaultRequestParams: defaultParams,
         ImageID:              d.Id(),
     }
-    err = m.Service.ImageServicer.Delete(deleteOpts)
+    err = m.Service.ImageServicer.Delete(ctx, deleteOpts)
     // Image not found
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             return fmt.Errorf("image %s not found", d.Id())
         }
 
@@ -180,18 +225,18 @@ func resourceImageDelete(d *schema.ResourceData, meta interface{}) (err error) {
     }
 
     w := wait{
-        Action: func() (interface{}, error) {
-            return m.Service.ImageServicer.Describe(
+        Action: deletedWaitAction(func() (interface{}, error) {
+            return m.Service.ImageServicer.Describe(ctx,
                 &service.ImageDescribeRequest{
                     DefaultRequestParams: defaultParams,
                     ImageIds:             []string{d.Id()},
                 })
-        },
-        CompareFn: defaultInverseWaitCompareFunc(),
+        }),
+        CompareFn: notFoundWaitCompareFunc(),
     }
-    _, err = w.Wait()
+    _, err = w.Wait(ctx)
     if err != nil {
-        return fmt.Errorf("error wait for state %s image: %s", d.Id(), err)
+        return fmt.Errorf("image %s is not confirmed to be deleted: %w", d.Id(), err)
     }
 
     d.SetId("")
@@ -0,0 +1,106 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+    "strings"
+    "terraform-provider-m3/service"
+    "terraform-provider-m3/simulator"
+    "testing"
+)
+
+func TestAccResourceImage(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    params := &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion}
+    describe := func(ctx context.Context, rs *terraform.ResourceState) error {
+        _, err := s.ImageServicer.Describe(ctx, &service.ImageDescribeRequest{
+            DefaultRequestParams: params,
+            ImageIds:             []string{rs.Primary.ID},
+        })
+        return err
+    }
+    instances := testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+}
+
+resource "m3_instance" "other" {
+	name  = "accother"
+	image = %q
+	shape = "SMALL"
+}
+`, simulator.DefaultImage, simulator.DefaultImage)
+    config := func(source string) string {
+        return instances + fmt.Sprintf(`
+resource "m3_image" "test" {
+	name               = "accimage"
+	source_instance_id = m3_instance.%s.id
+	description        = "acc image"
+}
+`, source)
+    }
+    var instanceID, imageID string
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_image", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: instances,
+                Check: func(state *terraform.State) error {
+                    instanceID = state.RootModule().Resources["m3_instance.test"].Primary.ID
+                    return nil
+                },
+            },
+            {
+                // image created outside of Terraform is imported without its source instance
+                PreConfig: func() {
+                    image, err := s.ImageServicer.Create(context.Background(), &service.ImageCreateRequest{
+                        DefaultRequestParams: params,
+                        InstanceID:           instanceID,
+                        ImageName:            "accimage",
+                        Description:          "acc image",
+                    })
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                    imageID = image.ImageID
+                },
+                Config:       config("test"),
+                ResourceName: "m3_image.test",
+                ImportState:  true,
+                ImportStateIdFunc: func(*terraform.State) (string, error) {
+                    return strings.Join([]string{testAccTenant, testAccRegion, imageID}, importIDSeparator), nil
+                },
+                ImportStatePersist: true,
+            },
+            {
+                // the first apply after import saves source instance without replacing the image
+                Config: config("test"),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        return resource.TestCheckResourceAttr("m3_image.test", "id", imageID)(state)
+                    },
+                    resource.TestCheckResourceAttrPair("m3_image.test", "source_instance_id", "m3_instance.test", "id"),
+                ),
+            },
+            {
+                Config: config("other"),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        if state.RootModule().Resources["m3_image.test"].Primary.ID == imageID {
+                            return fmt.Errorf("image %s is not replaced after change of source instance", imageID)
+                        }
+                        return nil
+                    },
+                    resource.TestCheckResourceAttrPair("m3_image.test", "source_instance_id", "m3_instance.other", "id"),
+                ),
+            },
+        },
+    })
+}
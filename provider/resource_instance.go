@@ -17,23 +17,38 @@
 package provider
 
 import (
+    "context"
     "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
     uuid "github.com/nu7hatch/gouuid"
+    "reflect"
     "regexp"
+    "sort"
+    "strings"
     "terraform-provider-m3/service"
     "terraform-provider-m3/utils"
+    "time"
 )
 
 func resourceInstance() *schema.Resource {
     return &schema.Resource{
-        Update:      resourceInstanceUpdate,
-        Create:      resourceInstanceCreate,
-        Read:        resourceInstanceRead,
-        Delete:      resourceInstanceDelete,
-        Description: "Creates instances of the specified configuration",
+        UpdateContext: withDiagnostics(resourceInstanceUpdate),
+        CreateContext: withDiagnostics(resourceInstanceCreate),
+        ReadContext:   withDiagnostics(resourceInstanceRead),
+        DeleteContext: withDiagnostics(resourceInstanceDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceInstanceImport,
+        },
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(20 * time.Minute),
+            Read:   schema.DefaultTimeout(5 * time.Minute),
+            Update: schema.DefaultTimeout(20 * time.Minute),
+            Delete: schema.DefaultTimeout(20 * time.Minute),
+        },
+        CustomizeDiff: resourceInstanceCustomizeDiff,
+        Description:   "Creates instances of the specified configuration",
         Schema: map[string]*schema.Schema{
             "name": {
                 Type:        schema.TypeString,
@@ -46,20 +61,21 @@ func resourceInstance() *schema.Resource {
             "tenant": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the tenant where the instance is to be launched.",
             },
             "region": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the region where the instance is to be run.",
             },
             "image": {
                 Type:        schema.TypeString,
                 Required:    true,
-                ForceNew:    true,
-                Description: "The name of the image that will be used for the instance configuration.",
+                Description: "The name of the image that will be used for the instance configuration.\nInstances are replaced when the image is changed, unless the image is only referred by another ID, name or alias.",
             },
             "key": {
                 Type:        schema.TypeString,
@@ -71,8 +87,7 @@ func resourceInstance() *schema.Resource {
             "shape": {
                 Type:        schema.TypeString,
                 Required:    true,
-                ForceNew:    true,
-                Description: "Required if InstanceType is not specified. Instance shape is a Maestro name for a capacity configuration, mapped to InstanceType and corresponding attributes in other CPs. Some possible values: LARGE, MICRO, SMALL, etc.",
+                Description: "Required if InstanceType is not specified. Instance shape is a Maestro name for a capacity configuration, mapped to InstanceType and corresponding attributes in other CPs. Some possible values: LARGE, MICRO, SMALL, etc.\nShape is changed in place in AWS, AZURE, GOOGLE and OPEN_STACK clouds, instances are stopped for the change if the cloud requires it. Instances in other clouds are replaced.",
             },
             "enable_chef": {
                 Type:        schema.TypeBool,
@@ -88,15 +103,72 @@ func resourceInstance() *schema.Resource {
                 Default:     "",
                 Description: "The name of the chef application.",
             },
+            "user_data": {
+                Type:          schema.TypeString,
+                Optional:      true,
+                ForceNew:      true,
+                ConflictsWith: []string{"user_data_base64", "startup_script"},
+                ValidateFunc:  validateUserData,
+                StateFunc:     userDataHashSum,
+                Description: fmt.Sprintf("The data which is passed to the instance at boot, e.g. cloud-init configuration or a shell script, up to %d bytes. "+
+                    "Only its hash is stored in state.", maxUserDataSize),
+            },
+            "user_data_base64": {
+                Type:          schema.TypeString,
+                Optional:      true,
+                ForceNew:      true,
+                ConflictsWith: []string{"user_data", "startup_script"},
+                ValidateFunc:  validateUserDataBase64,
+                StateFunc:     userDataHashSum,
+                Description: fmt.Sprintf("The base64 encoded data which is passed to the instance at boot, for binary data such as gzip compressed cloud-init configuration, up to %d bytes decoded. "+
+                    "Only its hash is stored in state.", maxUserDataSize),
+            },
+            "startup_script": {
+                Type:          schema.TypeString,
+                Optional:      true,
+                ForceNew:      true,
+                ConflictsWith: []string{"user_data", "user_data_base64"},
+                Description:   "The file name of the script uploaded to the library of the tenant, which is run when the instance boots, e.g. the ID of `m3_script`.",
+            },
             "additional_data": {
                 Type:        schema.TypeMap,
                 Optional:    true,
                 ForceNew:    true,
-                Description: "The size of an additional storage volume in GB.",
+                Description: "Additional parameters of instance launch which are passed to Maestro as is, e.g. parameters required by chef profile or placement parameters which are not covered by placement blocks. Attributes of placement blocks take precedence over the same keys.",
                 Elem: &schema.Schema{
                     Type: schema.TypeString,
                 },
             },
+            "aws": placementSchema("aws", "Placement of the instance in AWS.", map[string]*schema.Schema{
+                "subnet_id": placementAttribute("The ID of the subnet to launch the instance in."),
+                "security_group_ids": {
+                    Type:        schema.TypeList,
+                    Optional:    true,
+                    ForceNew:    true,
+                    Description: "The IDs of security groups of the instance.",
+                    Elem: &schema.Schema{
+                        Type: schema.TypeString,
+                    },
+                },
+                "availability_zone": placementAttribute("The availability zone to launch the instance in."),
+            }),
+            "azure": placementSchema("azure", "Placement of the instance in Azure.", map[string]*schema.Schema{
+                "resource_group": placementAttribute("The name of the resource group of the instance."),
+                "vnet":           placementAttribute("The name of the virtual network of the instance."),
+                "subnet":         placementAttribute("The name of the subnet of the virtual network."),
+            }),
+            "google": placementSchema("google", "Placement of the instance in Google Cloud.", map[string]*schema.Schema{
+                "zone":       placementAttribute("The zone to launch the instance in."),
+                "network":    placementAttribute("The name of the VPC network of the instance."),
+                "subnetwork": placementAttribute("The name of the subnetwork of the VPC network."),
+            }),
+            "vsphere": placementSchema("vsphere", "Placement of the instance in vSphere by native IDs, which are described by `placement_data` of `m3_data_placement_params`.", map[string]*schema.Schema{
+                "cluster":       placementAttribute("The native ID of the cluster, `clusterId` of placement data."),
+                "datastore":     placementAttribute("The native ID of the datastore, `datastoreId` of placement data."),
+                "folder":        placementAttribute("The native ID of the folder, `folderId` of placement data."),
+                "resource_pool": placementAttribute("The native ID of the resource pool, `resPoolId` of placement data."),
+                "host":          placementAttribute("The native ID of the host, `hostId` of placement data."),
+            }),
             "owner": {
                 Type:        schema.TypeString,
                 Optional:    true,
@@ -107,40 +179,69 @@ func resourceInstance() *schema.Resource {
             "tags": {
                 Type:        schema.TypeMap,
                 Optional:    true,
-                Description: "Key value parameter simplifying instance identification.",
+                Description: "Key value parameter simplifying instance identification. Tags take precedence over `default_tags` of provider with the same keys.",
                 Elem: &schema.Schema{
                     Type: schema.TypeString,
                 },
             },
-            "instances_count": {
-                Type:        schema.TypeInt,
+            "tags_all": {
+                Type:        schema.TypeMap,
+                Computed:    true,
+                Description: "All tags of the instance, including default tags of provider.",
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
+            },
+            "ignore_tag_prefixes": {
+                Type:        schema.TypeList,
                 Optional:    true,
-                ForceNew:    true,
-                Default:     1,
-                Description: "The number of instances that will be run. The default value is 1 (used if the parameter is not specified).",
+                Description: "Tags with keys starting with any of the prefixes are neither shown as changes nor removed, unless they are configured in tags. It allows other tools to manage their own tags of the instance.",
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                },
             },
-            "stop_after": {
+            "propagate_tags_to_volumes": {
+                Type:        schema.TypeBool,
+                Optional:    true,
+                Default:     true,
+                Description: "Whether changes of tags are also applied to the volumes attached to the instance.",
+            },
+            "instances_count": {
                 Type:         schema.TypeInt,
                 Optional:     true,
-                ForceNew:     true,
-                Default:      0,
-                ValidateFunc: validation.IntBetween(0, 720),
-                Description:  "The expiration parameter which specifies when the machine will stop, in hours after creation.",
+                Default:      1,
+                ValidateFunc: validation.IntAtLeast(1),
+                Description:  "The number of instances that will be run. The default value is 1 (used if the parameter is not specified).\nInstances are launched or terminated without recreation of the others when the number is changed.",
+            },
+            "stop_after": {
+                Type:             schema.TypeString,
+                Optional:         true,
+                ValidateFunc:     validateExpiration,
+                DiffSuppressFunc: suppressEmptyExpiration,
+                Description:      "The expiration parameter which specifies when the machine will stop, either in hours after creation (up to 720) or as a time in RFC3339 format, e.g. `2030-01-02T15:04:05Z`.\nIt is changed or cleared without recreation of the instance.",
             },
             "terminate_after": {
-                Type:         schema.TypeInt,
-                Optional:     true,
-                ForceNew:     true,
-                Default:      0,
-                ValidateFunc: validation.IntBetween(0, 720),
-                Description:  "Termination parameter which specifies when the instance will be terminated, in hours after creation.",
+                Type:             schema.TypeString,
+                Optional:         true,
+                ValidateFunc:     validateExpiration,
+                DiffSuppressFunc: suppressEmptyExpiration,
+                Description:      "Termination parameter which specifies when the instance will be terminated, either in hours after creation (up to 720) or as a time in RFC3339 format, e.g. `2030-01-02T15:04:05Z`.\nIt is changed or cleared without recreation of the instance.",
+            },
+            "stop_at": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The time in RFC3339 format when the instance will stop, it is empty if the stop is not scheduled.",
+            },
+            "terminate_at": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The time in RFC3339 format when the instance will be terminated, it is empty if the termination is not scheduled.",
             },
             "lock_termination": {
                 Type:        schema.TypeBool,
                 Optional:    true,
-                ForceNew:    true,
                 Default:     false,
-                Description: "Locking the instance from termination.\nAllowed for clouds: [AWS, AZURE, GOOGLE].",
+                Description: "Locking the instance from termination, it is changed without recreation of the instance.\nAllowed for clouds: [AWS, AZURE, GOOGLE].",
             },
             "cloud": {
                 Type:        schema.TypeString,
@@ -148,11 +249,102 @@ func resourceInstance() *schema.Resource {
                 ForceNew:    true,
                 Description: "The cloud. \nAllowed values: [AWS, AZURE, GOOGLE, NUTANIX, OPEN_STACK, VSPHERE, VMWARE, YANDEX].",
             },
+            "power_state": {
+                Type:     schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ValidateFunc: validation.StringInSlice([]string{
+                    service.InstanceStates.Running, service.InstanceStates.Stopped}, false),
+                Description: "The desired power state of the instance, it is changed without recreation of the instance.\nAllowed values: [running, stopped].",
+            },
+            "state": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The instance state, e.g. running or stopped.",
+            },
+            "private_ip": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The private IP address of the instance.",
+            },
+            "architecture": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The processor architecture of the instance.",
+            },
+            "availability_zone": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The availability zone where the instance is running.",
+            },
+            "resource_group": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The resource group of the instance, for Azure cloud.",
+            },
+            "volume": instanceVolumeSchema(),
+            "volume_ids": {
+                Type:        schema.TypeList,
+                Elem:        &schema.Schema{Type: schema.TypeString},
+                Computed:    true,
+                Description: "The IDs of volumes attached to the instance.",
+            },
+            "created": {
+                Type:        schema.TypeString,
+                Computed:    true,
+                Description: "The creation date of the instance.",
+            },
+            "instance_ids": {
+                Type:        schema.TypeList,
+                Elem:        &schema.Schema{Type: schema.TypeString},
+                Computed:    true,
+                Description: "The IDs of all instances launched by the resource, the first one is the ID of the resource.",
+            },
+            "instances": {
+                Type:        schema.TypeList,
+                Computed:    true,
+                Description: "All instances launched by the resource.",
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "id": {
+                            Type:        schema.TypeString,
+                            Computed:    true,
+                            Description: "The instance ID.",
+                        },
+                        "state": {
+                            Type:        schema.TypeString,
+                            Computed:    true,
+                            Description: "The instance state.",
+                        },
+                        "private_ip": {
+                            Type:        schema.TypeString,
+                            Computed:    true,
+                            Description: "The private IP address of the instance.",
+                        },
+                        "availability_zone": {
+                            Type:        schema.TypeString,
+                            Computed:    true,
+                            Description: "The availability zone where the instance is running.",
+                        },
+                        "created": {
+                            Type:        schema.TypeString,
+                            Computed:    true,
+                            Description: "The creation date of the instance.",
+                        },
+                        "volume_ids": {
+                            Type:        schema.TypeMap,
+                            Computed:    true,
+                            Elem:        &schema.Schema{Type: schema.TypeString},
+                            Description: "IDs of volumes of `volume` blocks attached to the instance by volume names.",
+                        },
+                    },
+                },
+            },
         },
     }
 }
 
-func resourceInstanceCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceInstanceError.WrapP(&err)
     m := meta.(*Meta)
@@ -164,23 +356,213 @@ func resourceInstanceCreate(d *schema.ResourceData, meta interface{}) (err error
     if err != nil {
         return err
     }
-    owner, err := utils.GetOwner(d, m.Config)
+
+    defaultParams := &service.DefaultRequestParams{
+        TenantName: tenant,
+        Region:     region,
+    }
+    instances, err := resourceInstanceRun(ctx, m, d, defaultParams, d.Get("instances_count").(int))
+    if len(instances) == 0 {
+        return err
+    }
+    // instances launched by failed run are kept in state, so the resource is tainted and they are terminated
+    d.SetId(instances[0].InstanceID)
+    ids := make([]string, 0, len(instances))
+    for _, instance := range instances {
+        ids = append(ids, instance.InstanceID)
+    }
+    if setErr := d.Set("instance_ids", ids); setErr != nil {
+        return setErr
+    }
+    if err != nil {
+        return err
+    }
+    err = d.Set("lock_termination", instances[0].LockedTermination)
+    if err != nil {
+        return err
+    }
+    err = d.Set("cloud", instances[0].Cloud)
+    if err != nil {
+        return err
+    }
+    err = resourceInstanceWaitLaunched(ctx, m, d, defaultParams, ids)
     if err != nil {
         return err
     }
 
-    stopAfter := d.Get("stop_after").(int)
-    terminateAfter := d.Get("terminate_after").(int)
-    if terminateAfter != 0 && stopAfter != 0 && stopAfter >= terminateAfter {
-        return fmt.Errorf("impossible stop instance: %s ,when it will be terminate.", d.Get("instance_name").(string))
+    m.Log.Info(fmt.Sprintf("Instance created ID: %s", d.Id()))
+    return resourceInstanceRead(ctx, d, meta)
+}
+
+func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
+    defer ReadingError.WrapP(&err)
+    defer ResourceInstanceError.WrapP(&err)
+
+    m := meta.(*Meta)
+    tenant, err := utils.GetTenant(d, m.Config)
+    if err != nil {
+        return err
+    }
+    region, err := utils.GetRegion(d, m.Config)
+    if err != nil {
+        return err
     }
 
     defaultParams := &service.DefaultRequestParams{
         TenantName: tenant,
         Region:     region,
     }
+
+    // all instances are described by one batch request, instances which are not found are omitted
+    listed, err := m.Service.InstanceServicer.List(ctx, &service.InstanceDescribeRequest{
+        DefaultRequestParams: defaultParams,
+        InstanceIds:          resourceInstanceIDs(d),
+    })
+    if err != nil {
+        return err
+    }
+    instances := make([]*service.Instance, 0, len(listed))
+    for _, instance := range listed {
+        if instance.State == service.InstanceStates.Terminated {
+            m.Log.Info(fmt.Sprintf("Instance %s is terminated", instance.InstanceID))
+            continue
+        }
+        instances = append(instances, instance)
+    }
+    if len(instances) == 0 {
+        d.SetId("")
+        return nil
+    }
+
+    // the first of remaining instances becomes the resource if the first launched one is terminated outside of Terraform
+    instance := instances[0]
+    d.SetId(instance.InstanceID)
+    volumes, instanceVolumeIDs, err := resourceInstanceReadVolumes(ctx, m, d, defaultParams, instances)
+    if err != nil {
+        return err
+    }
+    ids := make([]interface{}, 0, len(instances))
+    described := make([]interface{}, 0, len(instances))
+    for n, i := range instances {
+        ids = append(ids, i.InstanceID)
+        described = append(described, map[string]interface{}{
+            "id":                i.InstanceID,
+            "state":             i.State,
+            "private_ip":        i.PrivateIP,
+            "availability_zone": i.AvailabilityZone,
+            "created":           i.Created,
+            "volume_ids":        instanceVolumeIDs[n],
+        })
+    }
+
+    image, err := resourceInstanceImage(ctx, m, defaultParams, d.Get("image").(string), instance.Image)
+    if err != nil {
+        return err
+    }
+    configuredTags := d.Get("tags").(map[string]interface{})
+    defaults := defaultTags(meta)
+    tags := make(map[string]interface{}, len(instance.Tags))
+    tagsAll := make(map[string]interface{}, len(instance.Tags))
+    for _, tag := range instance.Tags {
+        _, configured := configuredTags[tag.Key]
+        _, isDefault := defaults[tag.Key]
+        if !configured && !isDefault && resourceInstanceTagIgnored(d, tag.Key) {
+            continue
+        }
+        tagsAll[tag.Key] = tag.Value
+        // default tags are shown only in tags_all, unless they are overridden by resource
+        if configured || !isDefault {
+            tags[tag.Key] = tag.Value
+        }
+    }
+    volumeIDs := make([]interface{}, 0, len(instance.VolumesIds))
+    for _, volumeID := range instance.VolumesIds {
+        volumeIDs = append(volumeIDs, volumeID)
+    }
+
+    // arguments which are not returned in description of instance are kept as they are in state
+    chefProfile, key, owner := d.Get("chef_profile").(string), d.Get("key").(string), d.Get("owner").(string)
+    if instance.ChefProfile != "" {
+        chefProfile = instance.ChefProfile
+    }
+    if instance.KeyName != "" {
+        key = instance.KeyName
+    }
+    // owner defaults to user identifier, so it is kept empty unless it is configured
+    if instance.Owner != "" && (owner != "" || !strings.EqualFold(instance.Owner, m.Config.UserIdentifier)) {
+        owner = instance.Owner
+    }
+
+    if powerState := instancePowerState(instance.State); powerState != "" {
+        if err = d.Set("power_state", powerState); err != nil {
+            return err
+        }
+    }
+
+    return setAttributes(d, map[string]interface{}{
+        "tenant":            tenant,
+        "region":            region,
+        "name":              instance.InstanceName,
+        "image":             image,
+        "shape":             instance.Shape,
+        "cloud":             instance.Cloud,
+        "tags":              tags,
+        "tags_all":          tagsAll,
+        "lock_termination":  instance.LockedTermination,
+        "enable_chef":       instance.ChefEnabled || d.Get("enable_chef").(bool),
+        "chef_profile":      chefProfile,
+        "key":               key,
+        "owner":             owner,
+        "state":             instance.State,
+        "private_ip":        instance.PrivateIP,
+        "architecture":      instance.Architecture,
+        "availability_zone": instance.AvailabilityZone,
+        "resource_group":    instance.ResourceGroup,
+        "volume":            volumes,
+        "volume_ids":        volumeIDs,
+        "created":           instance.Created,
+        "stop_at":           instance.StopAt,
+        "terminate_at":      instance.TerminateAt,
+        "instances_count":   len(instances),
+        "instance_ids":      ids,
+        "instances":         described,
+    })
+}
+
+// resourceInstanceIDs returns IDs of all instances launched by resource
+func resourceInstanceIDs(d *schema.ResourceData) []string {
+    return instanceIDs(d.Get("instance_ids").([]interface{}), d.Id())
+}
+
+// instanceIDs converts value of instance_ids attribute,
+// imported resource has only its own ID until it is read
+func instanceIDs(values []interface{}, id string) []string {
+    ids := make([]string, 0, len(values))
+    for _, value := range values {
+        ids = append(ids, value.(string))
+    }
+    if len(ids) == 0 && id != "" {
+        ids = append(ids, id)
+    }
+    return ids
+}
+
+// resourceInstanceRun launches count instances of configuration
+func resourceInstanceRun(ctx context.Context, m *Meta, d *schema.ResourceData, defaultParams *service.DefaultRequestParams, count int) ([]*service.Instance, error) {
+    owner, err := utils.GetOwner(d, m.Config)
+    if err != nil {
+        return nil, err
+    }
+
+    // delayed actions given by time are set after launch
+    stopAfter, _ := expirationHours(d.Get("stop_after").(string))
+    terminateAfter, _ := expirationHours(d.Get("terminate_after").(string))
+
     u, err := uuid.NewV4()
-    instanceChefUUID := tenant + "." + region + "." + u.String()
+    if err != nil {
+        return nil, err
+    }
+    instanceChefUUID := defaultParams.TenantName + "." + defaultParams.Region + "." + u.String()
 
     opts := &service.InstanceRunRequest{
         DefaultRequestParams: defaultParams,
@@ -193,9 +575,11 @@ func resourceInstanceCreate(d *schema.ResourceData, meta interface{}) (err error
         InstanceChefUUID:     instanceChefUUID,
         ChefProfile:          d.Get("chef_profile").(string),
         LockedTermination:    d.Get("lock_termination").(bool),
-        AdditionalData:       d.Get("additional_data").(map[string]interface{}),
-        Tags:                 d.Get("tags").(map[string]interface{}),
-        InstancesCount:       d.Get("instances_count").(int),
+        AdditionalData:       placementAdditionalData(d),
+        Tags:                 mergeTags(m.DefaultTags, d.Get("tags").(map[string]interface{})),
+        InstancesCount:       count,
+        UserData:             resourceInstanceUserData(d),
+        StartupScript:        d.Get("startup_script").(string),
     }
     if stopAfter != 0 {
         opts.StopAfter = &service.StopAfter{StopAfter: stopAfter}
@@ -203,84 +587,297 @@ func resourceInstanceCreate(d *schema.ResourceData, meta interface{}) (err error
     if terminateAfter != 0 {
         opts.TerminateAfter = &service.TerminateAfter{TerminateAfter: terminateAfter}
     }
-    instance, err := m.Service.InstanceServicer.Run(opts)
+    return m.Service.InstanceServicer.Run(ctx, opts)
+}
+
+// resourceInstanceWaitLaunched waits until launched instances are running, creates and attaches configured volumes,
+// sets delayed actions given by time and stops instances if it is configured.
+// Instances failed to launch are kept in state, so the resource is tainted and replaced by the next apply
+func resourceInstanceWaitLaunched(ctx context.Context, m *Meta, d *schema.ResourceData, defaultParams *service.DefaultRequestParams, ids []string) error {
+    for _, id := range ids {
+        err := resourceInstanceWaitState(ctx, m, defaultParams, id, service.InstanceStates.Running,
+            service.InstanceStates.Starting, service.InstanceStates.Cloning)
+        if err != nil {
+            return err
+        }
+    }
+    if len(instanceVolumes(d.Get("volume"))) != 0 {
+        for _, id := range ids {
+            if err := resourceInstanceApplyVolumes(ctx, m, d, defaultParams, id); err != nil {
+                return err
+            }
+        }
+    }
+    _, stopInHours := expirationHours(d.Get("stop_after").(string))
+    _, terminateInHours := expirationHours(d.Get("terminate_after").(string))
+    if !stopInHours || !terminateInHours {
+        for _, id := range ids {
+            if err := resourceInstanceSetExpiration(ctx, m, d, defaultParams, id); err != nil {
+                return err
+            }
+        }
+    }
+    if d.Get("power_state").(string) != service.InstanceStates.Stopped {
+        return nil
+    }
+    for _, id := range ids {
+        if err := resourceInstanceSetPowerState(ctx, m, defaultParams, id, service.InstanceStates.Stopped); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// resourceInstanceSetExpiration sets or clears delayed stop and terminate of instance,
+// delayed actions given in hours are counted from creation of the instance
+func resourceInstanceSetExpiration(ctx context.Context, m *Meta, d *schema.ResourceData, defaultParams *service.DefaultRequestParams, id string) error {
+    instance, err := m.Service.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+        DefaultRequestParams: defaultParams,
+        InstanceIds:          []string{id},
+    })
     if err != nil {
         return err
     }
-    d.SetId(instance.InstanceID)
-    err = d.Set("lock_termination", instance.LockedTermination)
+    created := instanceCreated(instance.Created)
+    stopAt, err := expirationTime(d.Get("stop_after").(string), created)
     if err != nil {
         return err
     }
-    err = d.Set("cloud", instance.Cloud)
+    terminateAt, err := expirationTime(d.Get("terminate_after").(string), created)
     if err != nil {
         return err
     }
-    w := wait{
-        Action: func() (interface{}, error) {
-            instance, err := m.Service.InstanceServicer.Describe(
-                &service.InstanceDescribeRequest{
-                    DefaultRequestParams: defaultParams,
-                    InstanceIds:          []string{d.Id()},
-                })
-            if err != nil {
-                return nil, err
+
+    m.Log.Info(fmt.Sprintf("Changing expiration of instance %s", id))
+    return m.Service.InstanceServicer.ManageExpiration(ctx, &service.InstanceExpirationRequest{
+        DefaultRequestParams: defaultParams,
+        InstanceID:           id,
+        StopAt:               formatExpirationTime(stopAt),
+        TerminateAt:          formatExpirationTime(terminateAt),
+    })
+}
+
+// resourceInstanceTerminate terminates instances and waits until they are terminated or not found.
+// Instances which are already not found are considered terminated
+func resourceInstanceTerminate(ctx context.Context, m *Meta, d *schema.ResourceData, defaultParams *service.DefaultRequestParams, ids []string) error {
+    terminating := make([]string, 0, len(ids))
+    // volumes of volume blocks are not deleted by every cloud together with instance
+    volumes := make(map[string][]string, len(ids))
+    for _, id := range ids {
+        configured, err := resourceInstanceConfiguredVolumes(ctx, m, d, defaultParams, id)
+        if err != nil {
+            return err
+        }
+        volumes[id] = configured
+
+        m.Log.Info(fmt.Sprintf("Deleting instance: %s", id))
+
+        terminateOpts := &service.InstanceTerminateRequest{
+            DefaultRequestParams: defaultParams,
+            InstanceID:           id,
+        }
+        if d.Get("lock_termination").(bool) {
+            err := resourceInstanceLockTermination(ctx, m, defaultParams, id, false)
+            if errors.Is(err, service.ErrNotFound) {
+                m.Log.Info(fmt.Sprintf("Instance %s not found", id))
+                continue
             }
-            if instance.State != service.InstanceStates.Running {
-                return nil, errors.New("instance state: not running")
+            if err != nil {
+                return err
             }
-            return instance, nil
-        },
-        CompareFn: defaultWaitCompareFunc(),
+        }
+
+        err = m.Service.InstanceServicer.Terminate(ctx, terminateOpts)
+        if errors.Is(err, service.ErrNotFound) {
+            m.Log.Info(fmt.Sprintf("Instance %s not found", id))
+            continue
+        }
+        if err != nil {
+            return err
+        }
+        terminating = append(terminating, id)
     }
-    _, err = w.Wait()
-    if err != nil {
-        return err
+
+    for _, id := range terminating {
+        id := id
+        w := stateWait{
+            Resource: "instance " + id,
+            // instance may be reported in previous state until backend processes the request
+            Pending: []string{
+                service.InstanceStates.Terminating,
+                service.InstanceStates.Running,
+                service.InstanceStates.Starting,
+                service.InstanceStates.Stopping,
+                service.InstanceStates.Stopped,
+            },
+            Target: []string{service.InstanceStates.Terminated},
+            Failed: []string{service.InstanceStates.Error},
+            Refresh: func() (interface{}, string, string, error) {
+                instance, err := m.Service.InstanceServicer.Describe(ctx,
+                    &service.InstanceDescribeRequest{
+                        DefaultRequestParams: defaultParams,
+                        InstanceIds:          []string{id},
+                    })
+                switch {
+                case errors.Is(err, service.ErrNotFound):
+                    return nil, service.InstanceStates.Terminated, "", nil
+                case err != nil:
+                    return nil, "", "", err
+                }
+                return instance, instance.State, instance.StateReason, nil
+            },
+        }
+        if _, err := w.Wait(ctx); err != nil {
+            return fmt.Errorf("instance %s is not confirmed to be terminated and may be left running: %s", id, err)
+        }
+        m.Log.Info(fmt.Sprintf("Instance terminated: %s", id))
+        for _, volumeID := range volumes[id] {
+            if err := resourceInstanceRemoveVolume(ctx, m, defaultParams, id, volumeID); err != nil {
+                return err
+            }
+        }
     }
+    return nil
+}
 
-    m.Log.Info(fmt.Sprintf("Instance created ID: %s", d.Id()))
-    return resourceInstanceRead(d, meta)
+// resourceInstanceLockTermination enables or disables termination protection of instance
+func resourceInstanceLockTermination(ctx context.Context, m *Meta, defaultParams *service.DefaultRequestParams, id string, lock bool) error {
+    action := service.TerminationProtectionActions.Disable
+    if lock {
+        action = service.TerminationProtectionActions.Enable
+    }
+    return m.Service.InstanceServicer.ManageTerminationProtection(ctx, &service.InstanceTerminationProtectionRequest{
+        DefaultRequestParams: defaultParams,
+        InstanceID:           id,
+        Action:               action,
+    })
 }
 
-func resourceInstanceRead(d *schema.ResourceData, meta interface{}) (err error) {
-    defer ReadingError.WrapP(&err)
-    defer ResourceInstanceError.WrapP(&err)
+// resourceInstanceCustomizeDiff checks that termination protection and placement are supported by cloud and schedule of instance is possible,
+// checks volumes, checks that image, chef profile, key and startup script exist, plans tags merged with default tags of provider, replaces instances
+// on change of image and on change of shape if cloud can not resize them and marks lists of instances as changing when instances are launched or terminated
+func resourceInstanceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("tags") {
+        if err := d.SetNewComputed("tags_all"); err != nil {
+            return err
+        }
+    } else if tagsAll := mergeTags(defaultTags(meta), d.Get("tags").(map[string]interface{})); !reflect.DeepEqual(tagsAll, d.Get("tags_all")) {
+        if err := d.SetNew("tags_all", tagsAll); err != nil {
+            return err
+        }
+    }
 
-    m := meta.(*Meta)
-    tenant, err := utils.GetTenant(d, m.Config)
-    if err != nil {
+    cloud := d.Get("cloud").(string)
+    if m, ok := meta.(*Meta); ok && cloud == "" {
+        cloud = m.Config.Cloud
+    }
+    if d.Get("lock_termination").(bool) && cloud != "" && !service.IsTerminationProtectionSupported(cloud) {
+        return fmt.Errorf("lock_termination is not supported for cloud %s, allowed clouds: [%s]",
+            cloud, strings.Join(service.TerminationProtectionClouds, ", "))
+    }
+    if cloud != "" {
+        if err := checkPlacementCloud(d, cloud); err != nil {
+            return err
+        }
+    }
+
+    if err := resourceInstanceCheckExpiration(d); err != nil {
         return err
     }
-    region, err := utils.GetRegion(d, m.Config)
-    if err != nil {
+    if err := resourceInstanceCheckVolumes(d); err != nil {
         return err
     }
 
-    opts := &service.InstanceDescribeRequest{
-        DefaultRequestParams: &service.DefaultRequestParams{
-            TenantName: tenant,
-            Region:     region,
-        },
-        InstanceIds: []string{d.Id()},
+    // images are listed once for both checks of image
+    var listImages imageLister
+    if m, ok := meta.(*Meta); ok && m.Service != nil {
+        listImages = resourceInstanceImageLister(ctx, d, m)
+        if err := resourceInstanceCheckDependencies(ctx, d, m, listImages); err != nil {
+            return err
+        }
     }
 
-    instance, err := m.Service.InstanceServicer.Describe(opts)
+    if d.Id() != "" && d.HasChange("image") {
+        if err := resourceInstanceCheckImageChange(d, listImages); err != nil {
+            return err
+        }
+    }
+    if d.Id() != "" && d.HasChange("shape") {
+        if !service.IsResizeSupported(cloud) {
+            if err := d.ForceNew("shape"); err != nil {
+                return err
+            }
+        }
+    }
 
-    if err != nil && err.Error() != "404" {
+    if d.Id() == "" || !d.HasChange("instances_count") {
+        return nil
+    }
+    if err := d.SetNewComputed("instance_ids"); err != nil {
         return err
     }
-    if instance == nil {
-        d.SetId("")
+    return d.SetNewComputed("instances")
+}
+
+// resourceInstanceCheckExpiration checks that changed delayed actions are in the future and instance is stopped before
+// it is terminated, then marks times of the actions as changing
+func resourceInstanceCheckExpiration(d *schema.ResourceDiff) error {
+    if !d.NewValueKnown("stop_after") || !d.NewValueKnown("terminate_after") {
         return nil
     }
+    // new instance is created at apply, so it is checked against current time
+    created := time.Now()
+    if d.Id() != "" {
+        created = instanceCreated(d.Get("created").(string))
+    }
+
+    times := make(map[string]time.Time, 2)
+    for _, key := range []string{"stop_after", "terminate_after"} {
+        at, err := expirationTime(d.Get(key).(string), created)
+        if err != nil {
+            return err
+        }
+        times[key] = at
+        if d.Id() != "" && !d.HasChange(key) {
+            continue
+        }
+        if !at.IsZero() && at.Before(time.Now()) {
+            return fmt.Errorf("%s (%s) is in the past, instance is created at %s", key, d.Get(key).(string), formatExpirationTime(created))
+        }
+        if d.Id() != "" {
+            if err := d.SetNewComputed(strings.Replace(key, "_after", "_at", 1)); err != nil {
+                return err
+            }
+        }
+    }
+
+    stopAt, terminateAt := times["stop_after"], times["terminate_after"]
+    if !stopAt.IsZero() && !terminateAt.IsZero() && !stopAt.Before(terminateAt) {
+        return fmt.Errorf("stop_after (%s) must be less than terminate_after (%s), instance can not be stopped after it is terminated",
+            d.Get("stop_after").(string), d.Get("terminate_after").(string))
+    }
     return nil
 }
 
-func resourceInstanceDelete(d *schema.ResourceData, meta interface{}) (err error) {
-    defer DeletingError.WrapP(&err)
-    defer ResourceInstanceError.WrapP(&err)
-
-    m := meta.(*Meta)
+// resourceInstanceCheckDependencies checks that image, chef profile, key and startup script of instance exist in tenant and region
+// when they are changed. Values which are not known until apply are not checked
+func resourceInstanceCheckDependencies(ctx context.Context, d *schema.ResourceDiff, m *Meta, listImages imageLister) error {
+    // tenant and region are unknown in plan unless they are configured, so they are checked in configuration
+    config := d.GetRawConfig()
+    known := func(key string) bool {
+        return config.IsNull() || config.GetAttr(key).IsWhollyKnown()
+    }
+    if !known("tenant") || !known("region") {
+        return nil
+    }
+    changed := func(keys ...string) bool {
+        for _, key := range keys {
+            if !known(key) {
+                return false
+            }
+        }
+        return d.Id() == "" || d.HasChanges(keys...)
+    }
     tenant, err := utils.GetTenant(d, m.Config)
     if err != nil {
         return err
@@ -289,62 +886,358 @@ func resourceInstanceDelete(d *schema.ResourceData, meta interface{}) (err error
     if err != nil {
         return err
     }
-
-    defaultParams := &service.DefaultRequestParams{
+    params := &service.DefaultRequestParams{
         TenantName: tenant,
         Region:     region,
     }
 
-    m.Log.Info(fmt.Sprintf("Deleting instance: %s", d.Id()))
-
-    terminateOpts := &service.InstanceTerminateRequest{
-        DefaultRequestParams: defaultParams,
-        InstanceID:           d.Id(),
+    if changed("image") {
+        if err = resourceInstanceCheckImage(listImages, params, d.Get("image").(string)); err != nil {
+            return err
+        }
     }
-    if d.Get("lock_termination").(bool) {
-        err := m.Service.InstanceServicer.UnlockTermination(terminateOpts)
+    if profile := d.Get("chef_profile").(string); profile != "" && changed("chef_profile", "additional_data") {
+        err = resourceInstanceCheckChefProfile(ctx, m, params, profile, placementAdditionalData(d))
         if err != nil {
-            if err.Error() == "404" {
-                return errors.New("instance not found")
-            }
             return err
         }
     }
+    cloud := d.Get("cloud").(string)
+    if cloud == "" {
+        cloud = m.Config.Cloud
+    }
+    if key := d.Get("key").(string); key != "" && changed("key") {
+        if err = resourceInstanceCheckKey(ctx, m, tenant, cloud, key); err != nil {
+            return err
+        }
+    }
+    if script := d.Get("startup_script").(string); script != "" && changed("startup_script") {
+        if err = resourceInstanceCheckScript(ctx, m, params, cloud, script); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// imageLister returns images of tenant and region of instance
+type imageLister func() (*[]service.Image, error)
+
+// resourceInstanceImageLister returns lister which requests images of tenant and region of instance only once
+func resourceInstanceImageLister(ctx context.Context, d utils.Getter, m *Meta) imageLister {
+    var (
+        images *[]service.Image
+        err    error
+        listed bool
+    )
+    return func() (*[]service.Image, error) {
+        if listed {
+            return images, err
+        }
+        listed = true
+        var tenant, region string
+        if tenant, err = utils.GetTenant(d, m.Config); err != nil {
+            return nil, err
+        }
+        if region, err = utils.GetRegion(d, m.Config); err != nil {
+            return nil, err
+        }
+        images, err = m.Service.DataImageGetList(ctx, &service.DefaultRequestParams{TenantName: tenant, Region: region})
+        return images, err
+    }
+}
+
+// resourceInstanceCheckImage checks that image is available by ID, name or alias
+func resourceInstanceCheckImage(listImages imageLister, params *service.DefaultRequestParams, image string) error {
+    images, err := listImages()
+    if err != nil {
+        return fmt.Errorf("can not check image %s: %s", image, err)
+    }
+    if images == nil {
+        return errors.New("empty result")
+    }
+    for _, i := range *images {
+        if i.ImageID == image || i.Name == image || i.Alias == image {
+            return nil
+        }
+    }
+    return fmt.Errorf("image %s is not found in tenant %s and region %s", image, params.TenantName, params.Region)
+}
 
-    err = m.Service.InstanceServicer.Terminate(terminateOpts)
-    // Instance not found
+// resourceInstanceCheckChefProfile checks that chef profile is a role of region and its required parameters are in additional data
+func resourceInstanceCheckChefProfile(ctx context.Context, m *Meta, params *service.DefaultRequestParams, profile string, additionalData map[string]interface{}) error {
+    chefs, err := m.Service.DataChefGetList(ctx, params)
     if err != nil {
-        if err.Error() == "404" {
-            return errors.New("instance  not found")
+        return fmt.Errorf("can not check chef profile %s: %s", profile, err)
+    }
+    if chefs == nil {
+        return errors.New("empty result")
+    }
+
+    roles := make([]string, 0, len(chefs.Roles))
+    for _, role := range chefs.Roles {
+        if role.RoleName != profile {
+            roles = append(roles, role.RoleName)
+            continue
+        }
+        missing := make([]string, 0, len(role.RequiredParameters))
+        for _, parameter := range role.RequiredParameters {
+            if _, ok := additionalData[parameter]; !ok {
+                missing = append(missing, parameter)
+            }
+        }
+        if len(missing) != 0 {
+            return fmt.Errorf("chef profile %s requires parameters in additional_data: [%s]", profile, strings.Join(missing, ", "))
         }
+        return nil
+    }
+    return fmt.Errorf("chef profile %s is not found, allowed values: [%s]", profile, strings.Join(roles, ", "))
+}
+
+// resourceInstanceCheckKey checks that key is available for tenant and cloud
+func resourceInstanceCheckKey(ctx context.Context, m *Meta, tenant, cloud, key string) error {
+    keypair, err := m.Service.KeypairServicer.Describe(ctx, &service.KeypairRequest{
+        Name:  key,
+        Email: m.Config.UserIdentifier,
+    })
+    if errors.Is(err, service.ErrNotFound) {
+        return fmt.Errorf("key %s is not found", key)
+    }
+    if err != nil {
+        return fmt.Errorf("can not check key %s: %s", key, err)
+    }
+    if !keypair.AllTenants && keypair.TenantName != "" && !strings.EqualFold(keypair.TenantName, tenant) {
+        return fmt.Errorf("key %s is not available for tenant %s", key, tenant)
+    }
+    if keypair.Cloud != "" && cloud != "" && !strings.EqualFold(keypair.Cloud, cloud) {
+        return fmt.Errorf("key %s is not available for cloud %s", key, cloud)
+    }
+    return nil
+}
+
+// resourceInstanceCheckScript checks that startup script is uploaded to the library of the tenant for cloud
+func resourceInstanceCheckScript(ctx context.Context, m *Meta, params *service.DefaultRequestParams, cloud, script string) error {
+    _, err := m.Service.ScriptServicer.Describe(ctx, &service.ScriptDescribeRequest{
+        DefaultRequestParams: params,
+        FileName:             script,
+        Email:                m.Config.UserIdentifier,
+        Cloud:                strings.ToUpper(cloud),
+    })
+    if errors.Is(err, service.ErrNotFound) {
+        return fmt.Errorf("startup script %s is not found in tenant %s and region %s", script, params.TenantName, params.Region)
+    }
+    if err != nil {
+        return fmt.Errorf("can not check startup script %s: %s", script, err)
+    }
+    return nil
+}
+
+// instancePowerState returns power state which instance has or is going to have,
+// it is empty for instances which are neither running nor stopped
+func instancePowerState(state string) string {
+    switch state {
+    case service.InstanceStates.Starting, service.InstanceStates.Running:
+        return service.InstanceStates.Running
+    case service.InstanceStates.Stopping, service.InstanceStates.Stopped:
+        return service.InstanceStates.Stopped
+    }
+    return ""
+}
+
+// resourceInstanceSetPowerState starts or stops instance and waits until it reaches power state
+func resourceInstanceSetPowerState(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, powerState string) error {
+    request := &service.InstancePowerRequest{
+        DefaultRequestParams: params,
+        InstanceID:           id,
+    }
+    action := m.Service.InstanceServicer.Start
+    // instance may be reported in previous state until backend processes the request
+    pending := []string{service.InstanceStates.Stopped, service.InstanceStates.Starting}
+    if powerState == service.InstanceStates.Stopped {
+        action = m.Service.InstanceServicer.Stop
+        pending = []string{service.InstanceStates.Running, service.InstanceStates.Stopping}
+    }
+
+    m.Log.Info(fmt.Sprintf("Changing power state of instance %s to %s", id, powerState))
+    if err := action(ctx, request); err != nil {
         return err
     }
+    return resourceInstanceWaitState(ctx, m, params, id, powerState, pending...)
+}
 
-    w := wait{
-        Action: func() (interface{}, error) {
-            return m.Service.InstanceServicer.Describe(
+// resourceInstanceWaitState waits until instance passes pending states and reaches state,
+// it fails immediately if instance gets into error or is terminated
+func resourceInstanceWaitState(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, state string, pending ...string) error {
+    w := stateWait{
+        Resource: "instance " + id,
+        Pending:  pending,
+        Target:   []string{state},
+        Failed: []string{
+            service.InstanceStates.Error,
+            service.InstanceStates.Terminating,
+            service.InstanceStates.Terminated,
+        },
+        Refresh: func() (interface{}, string, string, error) {
+            instance, err := m.Service.InstanceServicer.Describe(ctx,
                 &service.InstanceDescribeRequest{
-                    DefaultRequestParams: defaultParams,
-                    InstanceIds:          []string{d.Id()},
+                    DefaultRequestParams: params,
+                    InstanceIds:          []string{id},
                 })
+            if err != nil {
+                return nil, "", "", err
+            }
+            return instance, instance.State, instance.StateReason, nil
         },
-        CompareFn: defaultInverseWaitCompareFunc(),
     }
-    _, err = w.Wait()
+    _, err := w.Wait(ctx)
+    return err
+}
 
+// resourceInstanceResize changes shape of instance. Instance is stopped if cloud requires it,
+// then its previous power state is restored
+func resourceInstanceResize(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, shape string) error {
+    instance, err := m.Service.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+        DefaultRequestParams: params,
+        InstanceIds:          []string{id},
+    })
     if err != nil {
-        if err.Error() == "404" {
-            return nil
+        return err
+    }
+    if instance.Shape == shape {
+        return nil
+    }
+    powerState := instancePowerState(instance.State)
+    if powerState == "" {
+        return fmt.Errorf("instance %s can not be resized in state %s", id, instance.State)
+    }
+    if powerState != instance.State {
+        err = resourceInstanceWaitState(ctx, m, params, id, powerState, instance.State)
+        if err != nil {
+            return err
         }
-        return fmt.Errorf("error wait for state %s instance: %s", d.Id(), err)
+    }
+
+    stopped := false
+    if powerState == service.InstanceStates.Running && service.IsStopRequiredForResize(instance.Cloud) {
+        if err = resourceInstanceSetPowerState(ctx, m, params, id, service.InstanceStates.Stopped); err != nil {
+            return err
+        }
+        stopped = true
+    }
+
+    m.Log.Info(fmt.Sprintf("Changing shape of instance %s from %s to %s", id, instance.Shape, shape))
+    err = m.Service.InstanceServicer.Resize(ctx, &service.InstanceResizeRequest{
+        DefaultRequestParams: params,
+        InstanceID:           id,
+        Shape:                shape,
+    })
+    if err != nil {
+        return err
+    }
+
+    switch {
+    case stopped:
+        return resourceInstanceSetPowerState(ctx, m, params, id, service.InstanceStates.Running)
+    case powerState == service.InstanceStates.Running:
+        // running instance is rebooted with the new shape
+        return resourceInstanceWaitState(ctx, m, params, id, service.InstanceStates.Running,
+            service.InstanceStates.Stopping, service.InstanceStates.Stopped, service.InstanceStates.Starting)
+    }
+    return nil
+}
+
+// resourceInstanceImage returns image of instance for state. Configured image is kept while it refers to the image of instance
+// by ID, name or alias, otherwise name of the image is used, e.g. for imported instance
+func resourceInstanceImage(ctx context.Context, m *Meta, params *service.DefaultRequestParams, configured, imageID string) (string, error) {
+    if imageID == "" || configured == imageID {
+        return configured, nil
+    }
+    image, err := m.Service.ImageServicer.Describe(ctx, &service.ImageDescribeRequest{
+        DefaultRequestParams: params,
+        ImageIds:             []string{imageID},
+    })
+    if err != nil {
+        if !errors.Is(err, service.ErrNotFound) {
+            return "", err
+        }
+        // image was removed after instance launch, so its name is unknown
+        if configured != "" {
+            return configured, nil
+        }
+        return imageID, nil
+    }
+    if configured != "" && (image.Name == configured || image.Alias == configured) {
+        return configured, nil
+    }
+    if image.Name != "" {
+        return image.Name, nil
+    }
+    return imageID, nil
+}
+
+// resourceInstanceCheckImageChange replaces instances on change of image, unless the new value refers to the same image
+// by another ID, name or alias, e.g. after import, so the change is applied to state without recreation of instances
+func resourceInstanceCheckImageChange(d *schema.ResourceDiff, listImages imageLister) error {
+    before, after := d.GetChange("image")
+    if listImages != nil && d.NewValueKnown("image") {
+        images, err := listImages()
+        if err != nil {
+            return fmt.Errorf("can not check image %s: %s", after, err)
+        }
+        if images != nil {
+            imageID := func(value string) string {
+                for _, i := range *images {
+                    if i.ImageID == value || i.Name == value || i.Alias == value {
+                        return i.ImageID
+                    }
+                }
+                return ""
+            }
+            if id := imageID(before.(string)); id != "" && id == imageID(after.(string)) {
+                return nil
+            }
+        }
+    }
+    return d.ForceNew("image")
+}
+
+// resourceInstanceImport imports instance by ID in format tenant/region/instance_id
+func resourceInstanceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "tenant/region/instance_id")
+    if err != nil {
+        return nil, err
+    }
+    return importState(d, resourceInstance().Schema, parts["instance_id"], parts)
+}
+
+func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
+    defer DeletingError.WrapP(&err)
+    defer ResourceInstanceError.WrapP(&err)
+
+    m := meta.(*Meta)
+    tenant, err := utils.GetTenant(d, m.Config)
+    if err != nil {
+        return err
+    }
+    region, err := utils.GetRegion(d, m.Config)
+    if err != nil {
+        return err
+    }
+
+    defaultParams := &service.DefaultRequestParams{
+        TenantName: tenant,
+        Region:     region,
+    }
+
+    err = resourceInstanceTerminate(ctx, m, d, defaultParams, resourceInstanceIDs(d))
+    if err != nil {
+        return err
     }
 
     d.SetId("")
-    m.Log.Info(fmt.Sprintf("Instance terminated: %s", d.Id()))
     return nil
 }
 
-func resourceInstanceUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer UpdatingError.WrapP(&err)
     defer ResourceInstanceError.WrapP(&err)
 
@@ -363,43 +1256,167 @@ func resourceInstanceUpdate(d *schema.ResourceData, meta interface{}) (err error
         Region:     region,
     }
 
-    describeOpts := &service.InstanceDescribeRequest{
-        DefaultRequestParams: defaultParams,
-        InstanceIds:          []string{d.Id()},
+    // instance_ids is unknown in plan when number of instances is changed
+    old, _ := d.GetChange("instance_ids")
+    ids := instanceIDs(old.([]interface{}), d.Id())
+    count := d.Get("instances_count").(int)
+    if d.HasChange("lock_termination") {
+        // protection is changed before scale down, so terminated instances are unlocked according to new value
+        for _, id := range ids {
+            err = resourceInstanceLockTermination(ctx, m, defaultParams, id, d.Get("lock_termination").(bool))
+            if err != nil {
+                return err
+            }
+        }
+    }
+    if count < len(ids) {
+        // the last launched instances are terminated, so the first one remains the resource
+        if err = resourceInstanceTerminate(ctx, m, d, defaultParams, ids[count:]); err != nil {
+            return err
+        }
+        ids = ids[:count]
+        if err = d.Set("instance_ids", ids); err != nil {
+            return err
+        }
     }
 
-    instance, err := m.Service.InstanceServicer.Describe(describeOpts)
-
-    if len(d.Get("tags").(map[string]interface{})) < 1 {
-        tags := make([]string, 0, 4)
+    for _, id := range ids {
+        if d.HasChange("tags_all") {
+            instance, err := m.Service.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+                DefaultRequestParams: defaultParams,
+                InstanceIds:          []string{id},
+            })
+            if err != nil {
+                return err
+            }
+            if err = resourceInstanceUpdateTags(ctx, m, d, defaultParams, instance); err != nil {
+                return err
+            }
+        }
+        if d.HasChange("volume") {
+            if err = resourceInstanceApplyVolumes(ctx, m, d, defaultParams, id); err != nil {
+                return err
+            }
+        }
+        // instance is stopped before resize, so it is not started only to be stopped again
+        stopping := d.HasChange("power_state") && d.Get("power_state").(string) == service.InstanceStates.Stopped
+        if stopping {
+            err = resourceInstanceSetPowerState(ctx, m, defaultParams, id, service.InstanceStates.Stopped)
+            if err != nil {
+                return err
+            }
+        }
+        if d.HasChanges("stop_after", "terminate_after") {
+            if err = resourceInstanceSetExpiration(ctx, m, d, defaultParams, id); err != nil {
+                return err
+            }
+        }
+        if d.HasChange("shape") {
+            err = resourceInstanceResize(ctx, m, defaultParams, id, d.Get("shape").(string))
+            if err != nil {
+                return err
+            }
+        }
+        if d.HasChange("power_state") && !stopping {
+            err = resourceInstanceSetPowerState(ctx, m, defaultParams, id, d.Get("power_state").(string))
+            if err != nil {
+                return err
+            }
+        }
+    }
 
-        for _, tag := range instance.Tags {
-            tags = append(tags, fmt.Sprintf("%s=%s", tag.Key, tag.Value))
+    if count > len(ids) {
+        instances, err := resourceInstanceRun(ctx, m, d, defaultParams, count-len(ids))
+        launched := make([]string, 0, len(instances))
+        for _, instance := range instances {
+            launched = append(launched, instance.InstanceID)
         }
+        // instances launched by failed run are kept in state, so they are terminated by the next apply
+        if setErr := d.Set("instance_ids", append(ids, launched...)); setErr != nil {
+            return setErr
+        }
+        if err != nil {
+            return err
+        }
+        if err = resourceInstanceWaitLaunched(ctx, m, d, defaultParams, launched); err != nil {
+            return err
+        }
+    }
 
+    return resourceInstanceRead(ctx, d, meta)
+}
+
+// resourceInstanceUpdateTags applies changes of configured tags to instance,
+// tags which are not changed in configuration are kept as is
+func resourceInstanceUpdateTags(ctx context.Context, m *Meta, d *schema.ResourceData, defaultParams *service.DefaultRequestParams, instance *service.Instance) error {
+    remote := make(map[string]string, len(instance.Tags))
+    for _, tag := range instance.Tags {
+        remote[tag.Key] = tag.Value
+    }
+    var volumeIDs []string
+    if d.Get("propagate_tags_to_volumes").(bool) {
+        volumeIDs = instance.VolumesIds
+    }
+
+    o, n := d.GetChange("tags_all")
+    oldTags, newTags := o.(map[string]interface{}), n.(map[string]interface{})
+
+    removed := make([]string, 0, len(oldTags))
+    for key := range oldTags {
+        if _, ok := newTags[key]; ok || resourceInstanceTagIgnored(d, key) {
+            continue
+        }
+        if value, ok := remote[key]; ok {
+            removed = append(removed, fmt.Sprintf("%s=%s", key, value))
+        }
+    }
+    if len(removed) > 0 {
+        sort.Strings(removed)
         deleteOpts := &service.InstanceDeleteTagsRequest{
             DefaultRequestParams: defaultParams,
-            Id:                   d.Id(),
+            Id:                   instance.InstanceID,
             Cloud:                instance.Cloud,
             AvailabilityZone:     instance.AvailabilityZone,
             ResourceGroup:        instance.ResourceGroup,
-            Tags:                 tags,
+            VolumeIds:            volumeIDs,
+            Tags:                 removed,
+        }
+        if err := m.Service.InstanceServicer.DeleteTags(ctx, deleteOpts); err != nil {
+            return err
         }
+    }
 
-        return m.Service.InstanceServicer.DeleteTags(deleteOpts)
+    changed := make(map[string]interface{}, len(newTags))
+    for key, value := range newTags {
+        if current, ok := remote[key]; !ok || current != value.(string) {
+            changed[key] = value
+        }
+    }
+    if len(changed) == 0 {
+        return nil
     }
 
     updateOpts := &service.InstanceUpdateTagsRequest{
         DefaultRequestParams: defaultParams,
-        Id:                   d.Id(),
+        Id:                   instance.InstanceID,
         Cloud:                instance.Cloud,
         AvailabilityZone:     instance.AvailabilityZone,
         ResourceGroup:        instance.ResourceGroup,
-        VolumeIds:            instance.VolumesIds,
-        InstanceName:         d.Get("name").(string),
-        Tags:                 d.Get("tags").(map[string]interface{}),
-        Overwrite:            true,
+        VolumeIds:            volumeIDs,
+        InstanceName:         instance.InstanceName,
+        Tags:                 changed,
+        Overwrite:            false,
     }
 
-    return m.Service.InstanceServicer.UpdateTags(updateOpts)
+    return m.Service.InstanceServicer.UpdateTags(ctx, updateOpts)
+}
+
+// resourceInstanceTagIgnored checks if tag key starts with one of ignored prefixes
+func resourceInstanceTagIgnored(d *schema.ResourceData, key string) bool {
+    for _, prefix := range d.Get("ignore_tag_prefixes").([]interface{}) {
+        if prefix, ok := prefix.(string); ok && prefix != "" && strings.HasPrefix(key, prefix) {
+            return true
+        }
+    }
+    return false
 }
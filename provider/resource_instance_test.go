@@ -0,0 +1,984 @@
+package provider
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/golang/mock/gomock"
+    "github.com/hashicorp/go-hclog"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+    "net"
+    "reflect"
+    "regexp"
+    "strconv"
+    "strings"
+    "syscall"
+    "terraform-provider-m3/service"
+    smock "terraform-provider-m3/service/mock"
+    "terraform-provider-m3/simulator"
+    "testing"
+    "time"
+)
+
+func TestAccResourceInstance(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    describe := func(ctx context.Context, rs *terraform.ResourceState) error {
+        _, err := s.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+            DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+            InstanceIds:          []string{rs.Primary.ID},
+        })
+        return err
+    }
+    config := func(powerState string, lockTermination bool) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name             = "accinstance"
+	image            = %q
+	shape            = "SMALL"
+	power_state      = %q
+	lock_termination = %t
+	tags = {
+		env = "acc"
+	}
+}
+`, simulator.DefaultImage, powerState, lockTermination)
+    }
+    var instanceID string
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_instance", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: config(service.InstanceStates.Running, false),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "id"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "cloud", testAccCloud),
+                    resource.TestCheckResourceAttr("m3_instance.test", "state", service.InstanceStates.Running),
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume_ids.#", "1"),
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "private_ip"),
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "created"),
+                    func(state *terraform.State) error {
+                        rs := state.RootModule().Resources["m3_instance.test"]
+                        instanceID = rs.Primary.ID
+                        return describe(context.Background(), rs)
+                    },
+                ),
+            },
+            {
+                // tags changed in Maestro console must be shown in plan
+                PreConfig: func() {
+                    err := s.InstanceServicer.UpdateTags(context.Background(), &service.InstanceUpdateTagsRequest{
+                        DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+                        Id:                   instanceID,
+                        Overwrite:            true,
+                        Tags:                 map[string]interface{}{"env": "changed"},
+                    })
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                },
+                Config:             config(service.InstanceStates.Running, false),
+                PlanOnly:           true,
+                ExpectNonEmptyPlan: true,
+            },
+            {
+                Config: config(service.InstanceStates.Stopped, true),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        // in-place changes keep the instance
+                        return resource.TestCheckResourceAttr("m3_instance.test", "id", instanceID)(state)
+                    },
+                    resource.TestCheckResourceAttr("m3_instance.test", "lock_termination", "true"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "power_state", service.InstanceStates.Stopped),
+                    resource.TestCheckResourceAttr("m3_instance.test", "state", service.InstanceStates.Stopped),
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags.env", "acc"),
+                ),
+            },
+            {
+                ResourceName:      "m3_instance.test",
+                ImportState:       true,
+                ImportStateIdFunc: testAccImportID("m3_instance.test", testAccTenant, testAccRegion),
+                ImportStateVerify: true,
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_importReferences(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    config := func(image string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name         = "accinstance"
+	image        = %q
+	shape        = "SMALL"
+	enable_chef  = true
+	chef_profile = %q
+	owner        = "owner@example.com"
+}
+`, image, simulator.DefaultChefRole)
+    }
+    var instanceID string
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                // instance is launched outside of Terraform and imported with its arguments
+                PreConfig: func() {
+                    instances, err := s.InstanceServicer.Run(context.Background(), &service.InstanceRunRequest{
+                        DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+                        InstanceName:         "accinstance",
+                        Image:                "ami-centos7",
+                        Shape:                "SMALL",
+                        Owner:                "owner@example.com",
+                        ChefEnabled:          true,
+                        ChefProfile:          simulator.DefaultChefRole,
+                    })
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                    instanceID = instances[0].InstanceID
+                },
+                Config:            config(simulator.DefaultImage),
+                ResourceName:      "m3_instance.test",
+                ImportState:       true,
+                ImportStatePersist: true,
+                ImportStateIdFunc: func(*terraform.State) (string, error) {
+                    return strings.Join([]string{testAccTenant, testAccRegion, instanceID}, importIDSeparator), nil
+                },
+            },
+            {
+                // image is imported by name, other arguments are described by Maestro
+                Config:   config(simulator.DefaultImage),
+                PlanOnly: true,
+            },
+            {
+                // the same image referred by ID does not replace the instance
+                Config: config("ami-centos7"),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        return resource.TestCheckResourceAttr("m3_instance.test", "id", instanceID)(state)
+                    },
+                    resource.TestCheckResourceAttr("m3_instance.test", "image", "ami-centos7"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "chef_profile", simulator.DefaultChefRole),
+                    resource.TestCheckResourceAttr("m3_instance.test", "owner", "owner@example.com"),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_count(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    // describe succeeds while any of instances exists
+    describe := func(ctx context.Context, rs *terraform.ResourceState) (err error) {
+        for _, id := range testAccInstanceIDs(rs) {
+            _, err = s.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+                DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+                InstanceIds:          []string{id},
+            })
+            if err == nil {
+                return nil
+            }
+        }
+        return err
+    }
+    config := func(count int) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name            = "accinstances"
+	image           = %q
+	shape           = "SMALL"
+	instances_count = %d
+}
+`, simulator.DefaultImage, count)
+    }
+    var instanceIDs []string
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_instance", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: config(3),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "instance_ids.#", "3"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "instances.#", "3"),
+                    resource.TestCheckResourceAttrPair("m3_instance.test", "id", "m3_instance.test", "instance_ids.0"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "instances.2.state", service.InstanceStates.Running),
+                    func(state *terraform.State) error {
+                        instanceIDs = testAccInstanceIDs(state.RootModule().Resources["m3_instance.test"])
+                        return nil
+                    },
+                ),
+            },
+            {
+                Config: config(1),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "instance_ids.#", "1"),
+                    func(state *terraform.State) error {
+                        if id := state.RootModule().Resources["m3_instance.test"].Primary.ID; id != instanceIDs[0] {
+                            return fmt.Errorf("first instance %s is replaced with %s", instanceIDs[0], id)
+                        }
+                        for _, id := range instanceIDs[1:] {
+                            _, err := s.InstanceServicer.Describe(context.Background(), &service.InstanceDescribeRequest{
+                                DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+                                InstanceIds:          []string{id},
+                            })
+                            if !errors.Is(err, service.ErrNotFound) {
+                                return fmt.Errorf("instance %s is not terminated: %v", id, err)
+                            }
+                        }
+                        return nil
+                    },
+                ),
+            },
+            {
+                Config: config(2),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "instance_ids.#", "2"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "instances.1.state", service.InstanceStates.Running),
+                ),
+            },
+        },
+    })
+}
+
+// testAccInstanceIDs returns IDs of all instances launched by m3_instance resource
+func testAccInstanceIDs(rs *terraform.ResourceState) []string {
+    count, _ := strconv.Atoi(rs.Primary.Attributes["instance_ids.#"])
+    ids := make([]string, 0, count)
+    for i := 0; i < count; i++ {
+        ids = append(ids, rs.Primary.Attributes[fmt.Sprintf("instance_ids.%d", i)])
+    }
+    return ids
+}
+
+func TestAccResourceInstance_resize(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    describe := func(ctx context.Context, rs *terraform.ResourceState) error {
+        _, err := s.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+            DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+            InstanceIds:          []string{rs.Primary.ID},
+        })
+        return err
+    }
+    config := func(shape string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = %q
+}
+`, simulator.DefaultImage, shape)
+    }
+    var instanceID string
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_instance", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: config("SMALL"),
+                Check: func(state *terraform.State) error {
+                    instanceID = state.RootModule().Resources["m3_instance.test"].Primary.ID
+                    return nil
+                },
+            },
+            {
+                // instance is stopped for resize in AWS and started again with the new shape
+                Config: config("LARGE"),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        return resource.TestCheckResourceAttr("m3_instance.test", "id", instanceID)(state)
+                    },
+                    resource.TestCheckResourceAttr("m3_instance.test", "shape", "LARGE"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "state", service.InstanceStates.Running),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_volumes(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    config := func(volumes string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+	%s
+}
+`, simulator.DefaultImage, volumes)
+    }
+    var instanceID, logsID string
+    // volumes which are still configured are kept attached to the same instance
+    checkAttached := func(state *terraform.State) error {
+        rs := state.RootModule().Resources["m3_instance.test"]
+        if rs.Primary.ID != instanceID {
+            return fmt.Errorf("instance is replaced: %s instead of %s", rs.Primary.ID, instanceID)
+        }
+        volumes, err := s.VolumeServicer.List(context.Background(), &service.VolumeDescribeRequest{
+            DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+            InstanceId:           instanceID,
+        })
+        if err != nil {
+            return err
+        }
+        names := make([]string, 0, len(volumes))
+        for _, volume := range volumes {
+            if !volume.System {
+                names = append(names, volume.Name)
+            }
+        }
+        if want := rs.Primary.Attributes["volume.#"]; strconv.Itoa(len(names)) != want {
+            return fmt.Errorf("instance has volumes %v, expected %s", names, want)
+        }
+        return nil
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config: config(`
+	volume {
+		name       = "data"
+		size_in_gb = 10
+	}
+	volume {
+		name       = "logs"
+		size_in_gb = 5
+	}`),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        rs := state.RootModule().Resources["m3_instance.test"]
+                        instanceID = rs.Primary.ID
+                        logsID = rs.Primary.Attributes["volume.1.id"]
+                        return nil
+                    },
+                    checkAttached,
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume.#", "2"),
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "volume.0.id"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume_ids.#", "3"),
+                    resource.TestCheckResourceAttrPair("m3_instance.test", "instances.0.volume_ids.logs", "m3_instance.test", "volume.1.id"),
+                ),
+            },
+            {
+                // data volume is deleted, logs volume is resized and cache volume is added in place
+                Config: config(`
+	volume {
+		name       = "logs"
+		size_in_gb = 20
+	}
+	volume {
+		name       = "cache"
+		size_in_gb = 1
+	}`),
+                Check: resource.ComposeTestCheckFunc(
+                    checkAttached,
+                    func(state *terraform.State) error {
+                        return resource.TestCheckResourceAttr("m3_instance.test", "volume.0.id", logsID)(state)
+                    },
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume.0.size_in_gb", "20"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume.1.name", "cache"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume_ids.#", "3"),
+                ),
+            },
+            {
+                // added instance gets its own volumes
+                Config: config(`
+	instances_count = 2
+	volume {
+		name       = "logs"
+		size_in_gb = 20
+	}
+	volume {
+		name       = "cache"
+		size_in_gb = 1
+	}`),
+                Check: resource.ComposeTestCheckFunc(
+                    checkAttached,
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume.#", "2"),
+                    resource.TestCheckResourceAttrPair("m3_instance.test", "instances.0.volume_ids.cache", "m3_instance.test", "volume.1.id"),
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "instances.1.volume_ids.cache"),
+                    func(state *terraform.State) error {
+                        attributes := state.RootModule().Resources["m3_instance.test"].Primary.Attributes
+                        if attributes["instances.1.volume_ids.logs"] == attributes["instances.0.volume_ids.logs"] {
+                            return fmt.Errorf("instances share volume %s", attributes["instances.0.volume_ids.logs"])
+                        }
+                        return nil
+                    },
+                ),
+            },
+            {
+                Config: config(`
+	volume {
+		name       = "applogs"
+		size_in_gb = 20
+	}
+	volume {
+		name       = "cache"
+		size_in_gb = 1
+	}`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`volume logs can not be renamed to applogs`),
+            },
+            {
+                Config: config(`
+	volume {
+		name       = "logs"
+		size_in_gb = 10
+	}`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`size of volume logs can not be decreased from 20 to 10 GB`),
+            },
+            {
+                Config: config(""),
+                Check: resource.ComposeTestCheckFunc(
+                    checkAttached,
+                    resource.TestCheckResourceAttr("m3_instance.test", "volume_ids.#", "1"),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_volumesKeptOnTerminate(t *testing.T) {
+    server := testAccSimulator(t, func(opts *simulator.Options) {
+        opts.KeepVolumesOnTerminate = true
+    })
+    s := testAccService(server)
+    config := func(count int) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name            = "accinstance"
+	image           = %q
+	shape           = "SMALL"
+	instances_count = %d
+	volume {
+		name       = "data"
+		size_in_gb = 10
+	}
+}
+`, simulator.DefaultImage, count)
+    }
+    // volumes of terminated instances are detached by simulator, so only volumes of live instances are expected
+    checkVolumes := func(want int) func(*terraform.State) error {
+        return func(*terraform.State) error {
+            volumes, err := s.VolumeServicer.List(context.Background(), &service.VolumeDescribeRequest{
+                DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+            })
+            if err != nil {
+                return err
+            }
+            ids := make([]string, 0, len(volumes))
+            for _, volume := range volumes {
+                if !volume.System {
+                    ids = append(ids, volume.VolumeID)
+                }
+            }
+            if len(ids) != want {
+                return fmt.Errorf("got data volumes %v instead of %d", ids, want)
+            }
+            return nil
+        }
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      checkVolumes(0),
+        Steps: []resource.TestStep{
+            {
+                Config: config(2),
+                Check:  checkVolumes(2),
+            },
+            {
+                Config: config(1),
+                Check: resource.ComposeTestCheckFunc(
+                    checkVolumes(1),
+                    resource.TestCheckResourceAttr("m3_instance.test", "instance_ids.#", "1"),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_userData(t *testing.T) {
+    server := testAccSimulator(t)
+    config := func(boot string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_script" "init" {
+	name      = "acc_init"
+	extension = ".sh"
+	content   = "echo init"
+}
+
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+	%s
+}
+`, simulator.DefaultImage, boot)
+    }
+    var instanceID string
+    checkUserData := func(userData, startupScript string) resource.TestCheckFunc {
+        return func(state *terraform.State) error {
+            instanceID = state.RootModule().Resources["m3_instance.test"].Primary.ID
+            gotUserData, gotStartupScript := server.UserData(instanceID)
+            if gotUserData != userData || gotStartupScript != startupScript {
+                return fmt.Errorf("instance is launched with user data %q and startup script %q", gotUserData, gotStartupScript)
+            }
+            return nil
+        }
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config:      config(`startup_script = "missing.sh"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`startup script missing.sh is not found`),
+            },
+            {
+                Config: config(`startup_script = m3_script.init.id`),
+                Check: resource.ComposeTestCheckFunc(
+                    checkUserData("", "acc_init.sh"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "startup_script", "acc_init.sh"),
+                ),
+            },
+            {
+                Config:      config(`user_data_base64 = "echo hello"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`expected user_data_base64 to be base64 encoded`),
+            },
+            {
+                // only hash of user data is stored in state and instance is replaced when it is changed
+                Config: config(`user_data = "echo hello"`),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        previous := instanceID
+                        if err := checkUserData("echo hello", "")(state); err != nil {
+                            return err
+                        }
+                        if instanceID == previous {
+                            return fmt.Errorf("instance %s is not replaced", instanceID)
+                        }
+                        return nil
+                    },
+                    resource.TestCheckResourceAttr("m3_instance.test", "user_data", userDataHashSum("echo hello")),
+                ),
+            },
+            {
+                // added instances are launched with user data of configuration, not with its hash from state
+                Config: config(`user_data = "echo hello"
+	instances_count = 2`),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrPtr("m3_instance.test", "instance_ids.0", &instanceID),
+                    func(state *terraform.State) error {
+                        added := state.RootModule().Resources["m3_instance.test"].Primary.Attributes["instance_ids.1"]
+                        if gotUserData, _ := server.UserData(added); gotUserData != "echo hello" {
+                            return fmt.Errorf("added instance %s is launched with user data %q", added, gotUserData)
+                        }
+                        return nil
+                    },
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_expiration(t *testing.T) {
+    server := testAccSimulator(t)
+    config := func(expiration string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+	%s
+}
+`, simulator.DefaultImage, expiration)
+    }
+    var instanceID string
+    sameInstance := func(state *terraform.State) error {
+        return resource.TestCheckResourceAttr("m3_instance.test", "id", instanceID)(state)
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config: config(`stop_after = 4`),
+                Check: resource.ComposeTestCheckFunc(
+                    func(state *terraform.State) error {
+                        instanceID = state.RootModule().Resources["m3_instance.test"].Primary.ID
+                        return nil
+                    },
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "stop_at"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "terminate_at", ""),
+                ),
+            },
+            {
+                // lifetime is extended in place
+                Config: config("stop_after = 28\nterminate_after = \"2035-01-02T15:04:05Z\""),
+                Check: resource.ComposeTestCheckFunc(
+                    sameInstance,
+                    resource.TestCheckResourceAttrSet("m3_instance.test", "stop_at"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "terminate_at", "2035-01-02T15:04:05Z"),
+                ),
+            },
+            {
+                Config: config(""),
+                Check: resource.ComposeTestCheckFunc(
+                    sameInstance,
+                    resource.TestCheckResourceAttr("m3_instance.test", "stop_at", ""),
+                    resource.TestCheckResourceAttr("m3_instance.test", "terminate_at", ""),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_lockTerminationCloud(t *testing.T) {
+    server := testAccSimulator(t)
+    config := strings.Replace(testAccProviderConfig(server), strconv.Quote(testAccCloud), strconv.Quote("OPEN_STACK"), 1) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name             = "accinstance"
+	image            = %q
+	shape            = "SMALL"
+	lock_termination = true
+}
+`, simulator.DefaultImage)
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config:      config,
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`lock_termination is not supported for cloud OPEN_STACK`),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_placement(t *testing.T) {
+    server := testAccSimulator(t)
+    config := func(placement string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+	%s
+}
+`, simulator.DefaultImage, placement)
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config:      config(`azure { resource_group = "group" }`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`azure block is not allowed for cloud AWS, allowed clouds: \[AZURE\]`),
+            },
+            {
+                Config: config(`aws {
+		subnet_id          = "subnet-1"
+		security_group_ids = ["sg-1"]
+	}`),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "aws.0.subnet_id", "subnet-1"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "aws.0.security_group_ids.#", "1"),
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_planValidation(t *testing.T) {
+    server := testAccSimulator(t)
+    config := func(attributes string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	shape = "SMALL"
+	%s
+}
+`, attributes)
+    }
+    image := fmt.Sprintf("image = %q\n", simulator.DefaultImage)
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        Steps: []resource.TestStep{
+            {
+                Config:      config(image + "stop_after = 4\nterminate_after = 2"),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`stop_after \(4\) must be less than terminate_after \(2\)`),
+            },
+            {
+                Config:      config(`image = "missing-image"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`image missing-image is not found`),
+            },
+            {
+                Config:      config(image + `chef_profile = "missing-role"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`chef profile missing-role is not found`),
+            },
+            {
+                Config:      config(image + `chef_profile = "database"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`chef profile database requires parameters in additional_data: \[db_password\]`),
+            },
+            {
+                Config:      config(image + `key = "missing-key"`),
+                PlanOnly:    true,
+                ExpectError: regexp.MustCompile(`key missing-key is not found`),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_tags(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    params := &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion}
+    describe := func(ctx context.Context, rs *terraform.ResourceState) error {
+        _, err := s.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+            DefaultRequestParams: params,
+            InstanceIds:          []string{rs.Primary.ID},
+        })
+        return err
+    }
+    config := func(tags string) string {
+        return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name                = "accinstance"
+	image               = %q
+	shape               = "SMALL"
+	ignore_tag_prefixes = ["cost-"]
+	tags = {
+		%s
+	}
+}
+`, simulator.DefaultImage, tags)
+    }
+    var instance *service.Instance
+    checkRemoteTags := func(want map[string]string) resource.TestCheckFunc {
+        return func(state *terraform.State) (err error) {
+            instance, err = s.InstanceServicer.Describe(context.Background(), &service.InstanceDescribeRequest{
+                DefaultRequestParams: params,
+                InstanceIds:          []string{state.RootModule().Resources["m3_instance.test"].Primary.ID},
+            })
+            if err != nil {
+                return err
+            }
+            got := make(map[string]string, len(instance.Tags))
+            for _, tag := range instance.Tags {
+                got[tag.Key] = tag.Value
+            }
+            if !reflect.DeepEqual(got, want) {
+                return fmt.Errorf("got tags %v instead of %v", got, want)
+            }
+            return nil
+        }
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_instance", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: config(`env = "acc", team = "core"`),
+                Check:  checkRemoteTags(map[string]string{"env": "acc", "team": "core"}),
+            },
+            {
+                // tag of cost allocation tool must survive update
+                PreConfig: func() {
+                    err := s.InstanceServicer.UpdateTags(context.Background(), &service.InstanceUpdateTagsRequest{
+                        DefaultRequestParams: params,
+                        Id:                   instance.InstanceID,
+                        Tags:                 map[string]interface{}{"cost-center": "42"},
+                    })
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                },
+                Config: config(`env = "prod", owner = "ops"`),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckNoResourceAttr("m3_instance.test", "tags.cost-center"),
+                    checkRemoteTags(map[string]string{"env": "prod", "owner": "ops", "cost-center": "42"}),
+                    func(state *terraform.State) error {
+                        tags := server.VolumeTags(instance.VolumesIds[0])
+                        if tags["env"] != "prod" || tags["owner"] != "ops" {
+                            return fmt.Errorf("tags are not propagated to volume: %v", tags)
+                        }
+                        return nil
+                    },
+                ),
+            },
+        },
+    })
+}
+
+func TestAccResourceInstance_defaultTags(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    describe := func(ctx context.Context, rs *terraform.ResourceState) error {
+        _, err := s.InstanceServicer.Describe(ctx, &service.InstanceDescribeRequest{
+            DefaultRequestParams: &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion},
+            InstanceIds:          []string{rs.Primary.ID},
+        })
+        return err
+    }
+    config := func(team string) string {
+        return strings.Replace(testAccProviderConfig(server), `provider "m3" {`, fmt.Sprintf(`provider "m3" {
+	default_tags {
+		tags = {
+			team = %q
+			env  = "dev"
+		}
+	}`, team), 1) + fmt.Sprintf(`
+resource "m3_instance" "test" {
+	name  = "accinstance"
+	image = %q
+	shape = "SMALL"
+	tags = {
+		env = "prod"
+		app = "acc"
+	}
+}
+`, simulator.DefaultImage)
+    }
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy:      testAccCheckDestroy("m3_instance", describe),
+        Steps: []resource.TestStep{
+            {
+                Config: config("core"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags.%", "2"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags_all.%", "3"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags_all.team", "core"),
+                    // tags of resource take precedence over default tags
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags_all.env", "prod"),
+                ),
+            },
+            {
+                Config: config("platform"),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags.%", "2"),
+                    resource.TestCheckResourceAttr("m3_instance.test", "tags_all.team", "platform"),
+                ),
+            },
+        },
+    })
+}
+
+func TestResourceInstanceTerminate(t *testing.T) {
+    type (
+        MockBehavior func(m *smock.MockInstanceServicer)
+        TestCase     struct {
+            Name         string
+            WantErr      bool
+            MockBehavior MockBehavior
+        }
+    )
+
+    running := &service.Instance{InstanceID: "i-1", State: service.InstanceStates.Running}
+    testTable := []TestCase{
+        {
+            Name: "OK after network error",
+            MockBehavior: func(m *smock.MockInstanceServicer) {
+                m.EXPECT().Terminate(gomock.Any(), gomock.Any()).Return(nil)
+                m.EXPECT().Describe(gomock.Any(), gomock.Any()).Return(nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+                m.EXPECT().Describe(gomock.Any(), gomock.Any()).Return(nil, &service.Error{Kind: service.ErrNotFound})
+            },
+        },
+        {
+            Name: "OK if instance is already not found",
+            MockBehavior: func(m *smock.MockInstanceServicer) {
+                m.EXPECT().Terminate(gomock.Any(), gomock.Any()).Return(&service.Error{Kind: service.ErrNotFound})
+            },
+        },
+        {
+            Name:    "Got an error immediately if instance can not be described",
+            WantErr: true,
+            MockBehavior: func(m *smock.MockInstanceServicer) {
+                m.EXPECT().Terminate(gomock.Any(), gomock.Any()).Return(nil)
+                m.EXPECT().Describe(gomock.Any(), gomock.Any()).Return(nil, &service.Error{Kind: service.ErrPermissionDenied})
+            },
+        },
+        {
+            Name:    "Got an error if instance is still running on timeout",
+            WantErr: true,
+            MockBehavior: func(m *smock.MockInstanceServicer) {
+                m.EXPECT().Terminate(gomock.Any(), gomock.Any()).Return(nil)
+                m.EXPECT().Describe(gomock.Any(), gomock.Any()).Return(running, nil).MinTimes(1)
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockInstanceServicer := smock.NewMockInstanceServicer(ctl)
+            testCase.MockBehavior(mockInstanceServicer)
+
+            m := newMeta(&service.Service{InstanceServicer: mockInstanceServicer}, nil, hclog.NewNullLogger())
+            d := resourceInstance().TestResourceData()
+            ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+            defer cancel()
+
+            err := resourceInstanceTerminate(ctx, m, d, &service.DefaultRequestParams{}, []string{"i-1"})
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatalf("unexpected error '%v'", err)
+            }
+        })
+    }
+}
+
+func TestResourceInstanceImageLister(t *testing.T) {
+    ctl := gomock.NewController(t)
+    defer ctl.Finish()
+
+    images := &[]service.Image{{ImageID: "ami-1", Name: "ubuntu"}}
+    mockDataImageServicer := smock.NewMockDataImageServicer(ctl)
+    mockDataImageServicer.EXPECT().
+        DataImageGetList(gomock.Any(), &service.DefaultRequestParams{TenantName: testAccTenant, Region: testAccRegion}).
+        Return(images, nil).
+        Times(1)
+
+    m := newMeta(&service.Service{DataImageServicer: mockDataImageServicer}, nil, hclog.NewNullLogger())
+    d := schema.TestResourceDataRaw(t, resourceInstance().Schema, map[string]interface{}{
+        "tenant": testAccTenant,
+        "region": testAccRegion,
+    })
+    listImages := resourceInstanceImageLister(context.Background(), d, m)
+
+    if err := resourceInstanceCheckImage(listImages, &service.DefaultRequestParams{}, "ubuntu"); err != nil {
+        t.Fatal(err)
+    }
+    if got, err := listImages(); err != nil || got != images {
+        t.Fatalf("got %v, %v instead of listed images", got, err)
+    }
+}
@@ -17,6 +17,8 @@
 package provider
 
 import (
+    "context"
+    "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -24,14 +26,24 @@ import (
     "strings"
     "terraform-provider-m3/service"
     "terraform-provider-m3/utils"
+    "time"
 )
 
 func resourceKeypair() *schema.Resource {
     return &schema.Resource{
-        Create:      resourceKeypairCreate,
-        Read:        resourceKeypairRead,
-        Update:      resourceKeypairUpdate,
-        Delete:      resourceKeypairDelete,
+        CreateContext: withDiagnostics(resourceKeypairCreate),
+        ReadContext:   withDiagnostics(resourceKeypairRead),
+        UpdateContext: withDiagnostics(resourceKeypairUpdate),
+        DeleteContext: withDiagnostics(resourceKeypairDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceKeypairImport,
+        },
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(5 * time.Minute),
+            Read:   schema.DefaultTimeout(5 * time.Minute),
+            Update: schema.DefaultTimeout(5 * time.Minute),
+            Delete: schema.DefaultTimeout(5 * time.Minute),
+        },
         Description: "Registers an SSH key for further usage",
         Schema: map[string]*schema.Schema{
             "name": {
@@ -69,7 +81,7 @@ func resourceKeypair() *schema.Resource {
     }
 }
 
-func resourceKeypairCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceKeypairCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceKeypairError.WrapP(&err)
 
@@ -93,21 +105,30 @@ func resourceKeypairCreate(d *schema.ResourceData, meta interface{}) (err error)
         opts.KeypairCloud = &service.KeypairCloud{Cloud: strings.ToUpper(d.Get("cloud").(string))}
     }
 
-    keypair, err := m.Service.KeypairServicer.Create(&opts)
+    keypair, err := m.Service.KeypairServicer.Create(ctx, &opts)
     if err != nil {
         return err
     }
     if keypair == nil {
         m.Log.Info("Some troubles with keypair.")
     }
-    return resourceKeypairRead(d, meta)
-}
 
-func resourceKeypairRead(d *schema.ResourceData, meta interface{}) (err error) {
-    defer ReadingError.WrapP(&err)
-    defer ResourceKeypairError.WrapP(&err)
+    // keypair may be not described until backend processes the request
+    w := wait{
+        Action: func() (interface{}, error) {
+            return m.Service.KeypairServicer.Describe(ctx, keypairDescribeRequest(d, m))
+        },
+        CompareFn: defaultWaitCompareFunc(),
+    }
+    if _, err = w.Wait(ctx); err != nil {
+        return err
+    }
+    d.SetId(opts.Name)
+    return resourceKeypairRead(ctx, d, meta)
+}
 
-    m := meta.(*Meta)
+// keypairDescribeRequest returns request to describe keypair of resource
+func keypairDescribeRequest(d *schema.ResourceData, m *Meta) *service.KeypairRequest {
     opts := &service.KeypairRequest{
         Email: m.Config.UserIdentifier,
         Name:  d.Get("name").(string),
@@ -121,22 +142,49 @@ func resourceKeypairRead(d *schema.ResourceData, meta interface{}) (err error) {
     if d.Get("cloud").(string) != "" {
         opts.KeypairCloud = &service.KeypairCloud{Cloud: strings.ToUpper(d.Get("cloud").(string))}
     }
-    w := wait{
-        Action: func() (interface{}, error) {
-            return m.Service.KeypairServicer.Describe(opts)
-        },
-        CompareFn: defaultWaitCompareFunc(),
+    return opts
+}
+
+func resourceKeypairRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
+    defer ReadingError.WrapP(&err)
+    defer ResourceKeypairError.WrapP(&err)
+
+    m := meta.(*Meta)
+    keypair, err := m.Service.KeypairServicer.Describe(ctx, keypairDescribeRequest(d, m))
+    if err != nil {
+        if errors.Is(err, service.ErrNotFound) {
+            m.Log.Info(fmt.Sprintf("Keypair %s not found", d.Id()))
+            d.SetId("")
+            return nil
+        }
+        return err
     }
-    result, err := w.Wait()
-    if err != nil || result == nil {
+
+    d.SetId(keypair.Name)
+    if err = d.Set("public_key", keypair.PublicPart); err != nil {
+        return err
+    }
+    tenant := keypair.TenantName
+    if keypair.AllTenants {
+        tenant = ""
+    }
+    if err = d.Set("tenant", tenant); err != nil {
         return err
     }
+    return d.Set("cloud", keepCase(d, "cloud", keypair.Cloud))
+}
 
-    d.SetId(result.(*service.Keypair).Name)
-    return nil
+// resourceKeypairImport imports keypair registered for all tenants by its name
+// and keypair of specific tenant by ID in format tenant/name
+func resourceKeypairImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "name", "tenant/name")
+    if err != nil {
+        return nil, err
+    }
+    return importState(d, resourceKeypair().Schema, parts["name"], parts)
 }
 
-func resourceKeypairDelete(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceKeypairDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer DeletingError.WrapP(&err)
     defer ResourceKeypairError.WrapP(&err)
 
@@ -146,9 +194,9 @@ func resourceKeypairDelete(d *schema.ResourceData, meta interface{}) (err error)
         Email: m.Config.UserIdentifier,
     }
 
-    err = m.Service.KeypairServicer.Delete(opts)
+    err = m.Service.KeypairServicer.Delete(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             return fmt.Errorf("script %s not found", d.Id())
         }
 
@@ -158,15 +206,15 @@ func resourceKeypairDelete(d *schema.ResourceData, meta interface{}) (err error)
     return nil
 }
 
-func resourceKeypairUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceKeypairUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer UpdatingError.WrapP(&err)
     defer ResourceKeypairError.WrapP(&err)
 
-    if err := resourceKeypairDelete(d, meta); err != nil {
+    if err := resourceKeypairDelete(ctx, d, meta); err != nil {
         return err
     }
 
-    if err := resourceKeypairCreate(d, meta); err != nil {
+    if err := resourceKeypairCreate(ctx, d, meta); err != nil {
         return err
     }
     return nil
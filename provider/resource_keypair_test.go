@@ -0,0 +1,46 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+    "strings"
+    "terraform-provider-m3/service"
+    "testing"
+)
+
+func TestAccResourceKeypair(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+    publicKey := "ssh-rsa AAAAB3NzaC1yc2E" + strings.Repeat("A", 300) + " acc@example.com"
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy: testAccCheckDestroy("m3_keypair", func(ctx context.Context, rs *terraform.ResourceState) error {
+            _, err := s.KeypairServicer.Describe(ctx, &service.KeypairRequest{Name: rs.Primary.Attributes["name"]})
+            return err
+        }),
+        Steps: []resource.TestStep{
+            {
+                Config: testAccProviderConfig(server) + fmt.Sprintf(`
+resource "m3_keypair" "test" {
+	name       = "acc-keypair"
+	tenant     = %q
+	public_key = %q
+}
+`, testAccTenant, publicKey),
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttrSet("m3_keypair.test", "id"),
+                    resource.TestCheckResourceAttr("m3_keypair.test", "name", "acc-keypair"),
+                ),
+            },
+            {
+                ResourceName:      "m3_keypair.test",
+                ImportState:       true,
+                ImportStateIdFunc: testAccImportID("m3_keypair.test", testAccTenant),
+                ImportStateVerify: true,
+            },
+        },
+    })
+}
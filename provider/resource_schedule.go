@@ -17,6 +17,8 @@
 package provider
 
 import (
+    "context"
+    "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -28,21 +30,26 @@ import (
 
 func resourceSchedule() *schema.Resource {
     return &schema.Resource{
-        Create:      resourceScheduleCreate,
-        Read:        resourceScheduleRead,
-        Update:      resourceScheduleUpdate,
-        Delete:      resourceScheduleDelete,
+        CreateContext: withDiagnostics(resourceScheduleCreate),
+        ReadContext:   withDiagnostics(resourceScheduleRead),
+        UpdateContext: withDiagnostics(resourceScheduleUpdate),
+        DeleteContext: withDiagnostics(resourceScheduleDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceScheduleImport,
+        },
         Description: "Resource for configuring a schedule for instances start or stop.",
         Schema: map[string]*schema.Schema{
             "tenant": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the tenant where the schedule should be applied.",
             },
             "region": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The name of the region where the schedule should be applied.",
             },
@@ -69,6 +76,7 @@ func resourceSchedule() *schema.Resource {
             "cloud": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The cloud in which the schedule should be applied.\nAllowed values [ AWS, AZURE, GOOGLE, NUTANIX, OPEN_STACK, VSPHERE, VMWARE, YANDEX ].",
                 ValidateFunc: validation.StringInSlice([]string{
@@ -108,7 +116,7 @@ func resourceSchedule() *schema.Resource {
     }
 }
 
-func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceScheduleError.WrapP(&err)
 
@@ -133,7 +141,7 @@ func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) (err error
         },
         Name:         d.Get("name").(string),
         ScheduleName: strings.ToLower(d.Get("name").(string) + "::" + region),
-        Description:  d.Get("description").(string),
+        Description: d.Get("description").(string),
         Action:       strings.ToUpper(d.Get("action").(string)),
         Cron:         d.Get("cron").(string),
         Cloud:        strings.ToUpper(cloud),
@@ -161,7 +169,7 @@ func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) (err error
         opts.Type = "All my instances in region"
     }
 
-    schedule, err := m.Service.ScheduleServicer.Create(opts)
+    schedule, err := m.Service.ScheduleServicer.Create(ctx, opts)
     if err != nil {
         return err
     }
@@ -170,10 +178,10 @@ func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) (err error
         return fmt.Errorf("Some troubles with schedule: %s", d.Get("schedule_name"))
     }
     d.SetId(schedule.Name)
-    return resourceScheduleRead(d, meta)
+    return resourceScheduleRead(ctx, d, meta)
 }
 
-func resourceScheduleDelete(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer DeletingError.WrapP(&err)
     defer ResourceScheduleError.WrapP(&err)
 
@@ -202,9 +210,9 @@ func resourceScheduleDelete(d *schema.ResourceData, meta interface{}) (err error
         Cloud:        cloud,
     }
 
-    err = m.Service.ScheduleServicer.Delete(opts)
+    err = m.Service.ScheduleServicer.Delete(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             m.Log.Info(fmt.Sprintf("schedule %s not found", d.Get("name")))
             return nil
         }
@@ -215,7 +223,7 @@ func resourceScheduleDelete(d *schema.ResourceData, meta interface{}) (err error
     return nil
 }
 
-func resourceScheduleRead(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer ReadingError.WrapP(&err)
     defer ResourceScheduleError.WrapP(&err)
 
@@ -242,9 +250,9 @@ func resourceScheduleRead(d *schema.ResourceData, meta interface{}) (err error)
         Name:  d.Get("name").(string),
     }
 
-    _, err = m.Service.ScheduleServicer.Describe(opts)
+    schedule, err := m.Service.ScheduleServicer.Describe(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             m.Log.Info(fmt.Sprintf("Schedule %s not found", d.Get("name")))
             d.SetId("")
             return nil
@@ -252,18 +260,54 @@ func resourceScheduleRead(d *schema.ResourceData, meta interface{}) (err error)
         return err
     }
 
-    return nil
+    if err = setAttributes(d, map[string]interface{}{"tenant": tenant, "region": region, "cloud": cloud}); err != nil {
+        return err
+    }
+    if err = d.Set("description", schedule.Description); err != nil {
+        return err
+    }
+    if err = d.Set("action", keepCase(d, "action", schedule.Action)); err != nil {
+        return err
+    }
+    if err = d.Set("cron", schedule.Cron); err != nil {
+        return err
+    }
+    instancesID := make([]interface{}, 0, len(schedule.Instances))
+    for _, instance := range schedule.Instances {
+        instancesID = append(instancesID, instance.InstanceId)
+    }
+    if err = d.Set("instances_id", instancesID); err != nil {
+        return err
+    }
+    tagKey, tagValue := "", ""
+    if schedule.Tag != nil {
+        tagKey, tagValue = schedule.Tag.Key, schedule.Tag.Value
+    }
+    if err = d.Set("tag_key", tagKey); err != nil {
+        return err
+    }
+    return d.Set("tag_value", tagValue)
+}
+
+// resourceScheduleImport imports schedule by ID in format tenant/region/cloud/schedule_name
+func resourceScheduleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "tenant/region/cloud/schedule_name")
+    if err != nil {
+        return nil, err
+    }
+    parts["name"] = parts["schedule_name"]
+    return importState(d, resourceSchedule().Schema, parts["schedule_name"], parts)
 }
 
-func resourceScheduleUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScheduleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer UpdatingError.WrapP(&err)
     defer ResourceKeypairError.WrapP(&err)
 
-    if err := resourceScheduleDelete(d, meta); err != nil {
+    if err := resourceScheduleDelete(ctx, d, meta); err != nil {
         return err
     }
 
-    if err := resourceScheduleCreate(d, meta); err != nil {
+    if err := resourceScheduleCreate(ctx, d, meta); err != nil {
         return err
     }
     return nil
@@ -1,9 +1,12 @@
 package provider
 
 import (
+    "context"
+    "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+    "path/filepath"
     "regexp"
     "strings"
     "terraform-provider-m3/service"
@@ -12,10 +15,13 @@ import (
 
 func resourceScript() *schema.Resource {
     return &schema.Resource{
-        Create:      resourceScriptCreate,
-        Read:        resourceScriptRead,
-        Update:      resourceScriptUpdate,
-        Delete:      resourceScriptDelete,
+        CreateContext: withDiagnostics(resourceScriptCreate),
+        ReadContext:   withDiagnostics(resourceScriptRead),
+        UpdateContext: withDiagnostics(resourceScriptUpdate),
+        DeleteContext: withDiagnostics(resourceScriptDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceScriptImport,
+        },
         Description: "Upload script to the tenant's library in Maestro.",
         Schema: map[string]*schema.Schema{
             "name": {
@@ -34,12 +40,14 @@ func resourceScript() *schema.Resource {
             "tenant": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 Description: "The name of the tenant to which the script will be assigned.",
             },
 
             "region": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 Description: "The name of the region to which the script will be uploaded.",
             },
 
@@ -53,6 +61,7 @@ func resourceScript() *schema.Resource {
             "cloud": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 Description: "The cloud to which the script will be uploaded.\nAllowed values [ AWS, AZURE, GOOGLE, NUTANIX, OPEN_STACK, VSPHERE, VMWARE, YANDEX ].",
                 ValidateFunc: validation.StringInSlice([]string{
                     "AWS", "AZURE", "GOOGLE", "OPEN_STACK", "YANDEX",
@@ -62,7 +71,7 @@ func resourceScript() *schema.Resource {
     }
 }
 
-func resourceScriptCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScriptCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceScriptError.WrapP(&err)
 
@@ -94,17 +103,17 @@ func resourceScriptCreate(d *schema.ResourceData, meta interface{}) (err error)
         Email:         m.Config.UserIdentifier,
     }
 
-    script, err := m.Service.ScriptServicer.Create(opts)
+    script, err := m.Service.ScriptServicer.Create(ctx, opts)
     if err != nil {
         return err
     }
     if script == nil {
         m.Log.Info(fmt.Sprintf("some troubles with script: %s", d.Get("script_name").(string)))
     }
-    return resourceScriptRead(d, meta)
+    return resourceScriptRead(ctx, d, meta)
 }
 
-func resourceScriptRead(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScriptRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer ReadingError.WrapP(&err)
     defer ResourceScriptError.WrapP(&err)
 
@@ -132,16 +141,41 @@ func resourceScriptRead(d *schema.ResourceData, meta interface{}) (err error) {
         Cloud:    strings.ToUpper(cloud),
     }
 
-    script, err := m.Service.ScriptServicer.Describe(opts)
-    if err != nil || script == nil {
+    script, err := m.Service.ScriptServicer.Describe(ctx, opts)
+    if err != nil {
+        if errors.Is(err, service.ErrNotFound) {
+            m.Log.Info(fmt.Sprintf("Script %s not found", opts.FileName))
+            d.SetId("")
+            return nil
+        }
         return err
     }
 
     d.SetId(script.FileName)
-    return nil
+    if err = setAttributes(d, map[string]interface{}{"tenant": tenant, "region": region, "cloud": cloud}); err != nil {
+        return err
+    }
+    return d.Set("content", script.Content)
+}
+
+// resourceScriptImport imports script by ID in format tenant/region/cloud/file_name,
+// file name consists of script name and extension
+func resourceScriptImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "tenant/region/cloud/file_name")
+    if err != nil {
+        return nil, err
+    }
+    fileName := parts["file_name"]
+    extension := filepath.Ext(fileName)
+    if extension == "" {
+        return nil, fmt.Errorf("file name '%s' has no extension", fileName)
+    }
+    parts["name"] = strings.TrimSuffix(fileName, extension)
+    parts["extension"] = extension
+    return importState(d, resourceScript().Schema, fileName, parts)
 }
 
-func resourceScriptDelete(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScriptDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer DeletingError.WrapP(&err)
     defer ResourceScriptError.WrapP(&err)
 
@@ -169,9 +203,9 @@ func resourceScriptDelete(d *schema.ResourceData, meta interface{}) (err error)
         Cloud:    cloud,
     }
 
-    err = m.Service.ScriptServicer.Delete(opts)
+    err = m.Service.ScriptServicer.Delete(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             return fmt.Errorf("script: %s not found", d.Id())
         }
 
@@ -181,14 +215,14 @@ func resourceScriptDelete(d *schema.ResourceData, meta interface{}) (err error)
     return nil
 }
 
-func resourceScriptUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceScriptUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer UpdatingError.WrapP(&err)
     defer ResourceScriptError.WrapP(&err)
-    if err := resourceScriptDelete(d, meta); err != nil {
+    if err := resourceScriptDelete(ctx, d, meta); err != nil {
         return err
     }
 
-    if err := resourceScriptCreate(d, meta); err != nil {
+    if err := resourceScriptCreate(ctx, d, meta); err != nil {
         return err
     }
     return nil
@@ -0,0 +1,54 @@
+package provider
+
+import (
+    "context"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+    "terraform-provider-m3/service"
+    "testing"
+)
+
+func TestAccResourceScript(t *testing.T) {
+    server := testAccSimulator(t)
+    s := testAccService(server)
+
+    resource.Test(t, resource.TestCase{
+        ProviderFactories: testAccProviderFactories,
+        CheckDestroy: testAccCheckDestroy("m3_script", func(ctx context.Context, rs *terraform.ResourceState) error {
+            _, err := s.ScriptServicer.Describe(ctx, &service.ScriptDescribeRequest{FileName: rs.Primary.ID})
+            return err
+        }),
+        Steps: []resource.TestStep{
+            {
+                Config: testAccProviderConfig(server) + `
+resource "m3_script" "test" {
+	name      = "acc_script"
+	extension = ".sh"
+	content   = "echo hello"
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_script.test", "id", "acc_script.sh"),
+                ),
+            },
+            {
+                Config: testAccProviderConfig(server) + `
+resource "m3_script" "test" {
+	name      = "acc_script"
+	extension = ".sh"
+	content   = "echo updated"
+}
+`,
+                Check: resource.ComposeTestCheckFunc(
+                    resource.TestCheckResourceAttr("m3_script.test", "content", "echo updated"),
+                ),
+            },
+            {
+                ResourceName:      "m3_script.test",
+                ImportState:       true,
+                ImportStateIdFunc: testAccImportID("m3_script.test", testAccTenant, testAccRegion, testAccCloud),
+                ImportStateVerify: true,
+            },
+        },
+    })
+}
@@ -1,28 +1,41 @@
 package provider
 
 import (
+    "context"
+    "errors"
     "fmt"
     "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
     "terraform-provider-m3/service"
     "terraform-provider-m3/utils"
+    "time"
 )
 
 func resourceVolume() *schema.Resource {
     return &schema.Resource{
-        Create:      resourceVolumeCreate,
-        Read:        resourceVolumeRead,
-        Delete:      resourceVolumeDelete,
+        CreateContext: withDiagnostics(resourceVolumeCreate),
+        ReadContext:   withDiagnostics(resourceVolumeRead),
+        DeleteContext: withDiagnostics(resourceVolumeDelete),
+        Importer: &schema.ResourceImporter{
+            StateContext: resourceVolumeImport,
+        },
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(10 * time.Minute),
+            Read:   schema.DefaultTimeout(5 * time.Minute),
+            Delete: schema.DefaultTimeout(10 * time.Minute),
+        },
         Description: "Creates a new storage volume and attaches it to the specified instance.",
         Schema: map[string]*schema.Schema{
             "tenant": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The tenant name.",
             },
             "region": {
                 Type:        schema.TypeString,
                 Optional:    true,
+                Computed:    true,
                 ForceNew:    true,
                 Description: "The region name.",
             },
@@ -48,7 +61,7 @@ func resourceVolume() *schema.Resource {
     }
 }
 
-func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer CreatingError.WrapP(&err)
     defer ResourceVolumeError.WrapP(&err)
 
@@ -77,8 +90,8 @@ func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) (err error)
             VolumeName:           d.Get("name").(string),
             SizeInGB:             d.Get("size_in_gb").(int),
         }
-        volume, err = m.Service.VolumeServicer.Create(opts)
-        neededState = service.AvailableImageState
+        volume, err = m.Service.VolumeServicer.Create(ctx, opts)
+        neededState = service.AvailableVolumeState
     } else {
         opts := &service.VolumeCreateAndAttachRequest{
             DefaultRequestParams: defaultParams,
@@ -86,48 +99,27 @@ func resourceVolumeCreate(d *schema.ResourceData, meta interface{}) (err error)
             SizeInGB:             d.Get("size_in_gb").(int),
             InstanceId:           instanceId,
         }
-        volume, err = m.Service.VolumeServicer.CreateAndAttach(opts)
+        volume, err = m.Service.VolumeServicer.CreateAndAttach(ctx, opts)
         neededState = service.InUseState
     }
 
-    m.Log.Info(fmt.Sprintf("Creating volume: %s", d.Id()))
-
     if err != nil {
         return err
     }
+    m.Log.Info(fmt.Sprintf("Creating volume: %s", volume.VolumeID))
 
-    w := wait{
-        Action: func() (interface{}, error) {
-            volume, err := m.Service.VolumeServicer.Describe(
-                &service.VolumeDescribeRequest{
-                    DefaultRequestParams: defaultParams,
-                    VolumeIds:            []string{d.Id()},
-                    InstanceId:           instanceId,
-                })
-            if err != nil {
-                return nil, err
-            }
-            if volume.State != neededState {
-                return nil, fmt.Errorf("volume state: not %s", neededState)
-            }
-            return volume, nil
-        },
-        CompareFn: defaultWaitCompareFunc(),
-    }
-    _, err = w.Wait()
+    // volume is kept in state if it fails, so the resource is tainted and replaced by the next apply
+    d.SetId(volume.VolumeID)
+    err = volumeWaitState(ctx, m, defaultParams, d.Id(), instanceId, neededState)
     if err != nil {
-        if err.Error() == "404" {
-            return fmt.Errorf("volume %s not found", d.Get("volume_name").(string))
-        }
-        return fmt.Errorf("error wait for state, volume: %s", d.Get("volume_name").(string))
+        return fmt.Errorf("error wait for state: %s", err)
     }
-    d.SetId(volume.VolumeID)
 
     m.Log.Info(fmt.Sprintf("Volume created ID: %s", d.Id()))
-    return resourceVolumeRead(d, meta)
+    return resourceVolumeRead(ctx, d, meta)
 }
 
-func resourceVolumeRead(d *schema.ResourceData, meta interface{}) (err error) {
+func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer ReadingError.WrapP(&err)
     defer ResourceVolumeError.WrapP(&err)
 
@@ -141,30 +133,116 @@ func resourceVolumeRead(d *schema.ResourceData, meta interface{}) (err error) {
         return err
     }
 
-    instanceId := d.Get("instance_id").(string)
-    if len(instanceId) != 0 {
-        return nil
-    }
     opts := &service.VolumeDescribeRequest{
         DefaultRequestParams: &service.DefaultRequestParams{
             TenantName: tenant,
             Region:     region,
         },
-        VolumeIds: []string{d.Id()},
+        VolumeIds:  []string{d.Id()},
+        InstanceId: d.Get("instance_id").(string),
     }
 
-    _, err = m.Service.VolumeServicer.Describe(opts)
+    volume, err := m.Service.VolumeServicer.Describe(ctx, opts)
     if err != nil {
-        if err.Error() == "404" {
-            return err
+        if errors.Is(err, service.ErrNotFound) {
+            m.Log.Info(fmt.Sprintf("Volume %s not found", d.Id()))
+            d.SetId("")
+            return nil
         }
-        d.SetId("")
         return err
     }
-    return nil
+
+    if err = setAttributes(d, map[string]interface{}{"tenant": tenant, "region": region}); err != nil {
+        return err
+    }
+    if err = d.Set("name", volume.Name); err != nil {
+        return err
+    }
+    return d.Set("size_in_gb", volume.SizeLabel)
+}
+
+// volumeWaitState waits until volume reaches state, attached volume becomes available before it is in use
+// and resized or detached volume may be reported in previous state until backend processes the request
+func volumeWaitState(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, instanceID, state string) error {
+    _, err := stateWait{
+        Resource: "volume " + id,
+        Pending: []string{
+            service.CreatingVolumeState,
+            service.AvailableVolumeState,
+            service.InUseState,
+            service.DetachingVolumeState,
+            service.ModifyingVolumeState,
+        },
+        Target: []string{state},
+        Failed: []string{service.ErrorVolumeState},
+        Refresh: func() (interface{}, string, string, error) {
+            volume, err := m.Service.VolumeServicer.Describe(ctx,
+                &service.VolumeDescribeRequest{
+                    DefaultRequestParams: params,
+                    VolumeIds:            []string{id},
+                    InstanceId:           instanceID,
+                })
+            if err != nil {
+                return nil, "", "", err
+            }
+            return volume, volume.State, volume.StateReason, nil
+        },
+    }.Wait(ctx)
+    return err
+}
+
+// volumeWaitResized waits until volume has size and is in use again. Volume may be reported in use with the previous size
+// until backend starts modifying it, so it is considered modified until its size is changed
+func volumeWaitResized(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, instanceID string, size int) error {
+    _, err := stateWait{
+        Resource: "volume " + id,
+        Pending:  []string{service.ModifyingVolumeState},
+        Target:   []string{service.InUseState},
+        Failed:   []string{service.ErrorVolumeState},
+        Refresh: func() (interface{}, string, string, error) {
+            volume, err := m.Service.VolumeServicer.Describe(ctx,
+                &service.VolumeDescribeRequest{
+                    DefaultRequestParams: params,
+                    VolumeIds:            []string{id},
+                    InstanceId:           instanceID,
+                })
+            if err != nil {
+                return nil, "", "", err
+            }
+            if volume.State == service.InUseState && volume.SizeLabel < size {
+                return volume, service.ModifyingVolumeState, "", nil
+            }
+            return volume, volume.State, volume.StateReason, nil
+        },
+    }.Wait(ctx)
+    return err
 }
 
-func resourceVolumeDelete(d *schema.ResourceData, meta interface{}) (err error) {
+// volumeDetach detaches volume from instance and waits until it is available
+func volumeDetach(ctx context.Context, m *Meta, params *service.DefaultRequestParams, id, instanceID string) error {
+    m.Log.Info(fmt.Sprintf("Detaching volume %s from instance %s", id, instanceID))
+    err := m.Service.VolumeServicer.Detach(ctx, &service.VolumeDetachRequest{
+        DefaultRequestParams: params,
+        VolumeID:             id,
+        InstanceId:           instanceID,
+    })
+    if err != nil {
+        return err
+    }
+    return volumeWaitState(ctx, m, params, id, "", service.AvailableVolumeState)
+}
+
+// resourceVolumeImport imports volume by ID in format tenant/region/volume_id,
+// attached volume is imported by ID in format tenant/region/instance_id/volume_id
+func resourceVolumeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts, err := parseImportID(d.Id(), "tenant/region/volume_id", "tenant/region/instance_id/volume_id")
+    if err != nil {
+        return nil, err
+    }
+    return importState(d, resourceVolume().Schema, parts["volume_id"], parts)
+}
+
+func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) (err error) {
     defer DeletingError.WrapP(&err)
     defer ResourceVolumeError.WrapP(&err)
 
@@ -185,14 +263,22 @@ func resourceVolumeDelete(d *schema.ResourceData, meta interface{}) (err error)
 
     m.Log.Info("Deleting volume: %s", d.Id())
 
+    // attached volume can not be deleted, volume of terminated instance is not found or already detached
+    if instanceID := d.Get("instance_id").(string); instanceID != "" {
+        err = volumeDetach(ctx, m, defaultParams, d.Id(), instanceID)
+        if err != nil && !errors.Is(err, service.ErrNotFound) && !errors.Is(err, service.ErrValidation) {
+            return err
+        }
+    }
+
     deleteOpts := &service.VolumeDeleteRequest{
         DefaultRequestParams: defaultParams,
         VolumeID:             d.Id(),
     }
-    err = m.Service.VolumeServicer.Delete(deleteOpts)
+    err = m.Service.VolumeServicer.Delete(ctx, deleteOpts)
     // Volume not found
     if err != nil {
-        if err.Error() == "404" {
+        if errors.Is(err, service.ErrNotFound) {
             return fmt.Errorf("volume %s not found", d.Id())
         }
         d.SetId("")
@@ -200,18 +286,18 @@ func resourceVolumeDelete(d *schema.ResourceData, meta interface{}) (err error)
     }
 
     w := wait{
-        Action: func() (interface{}, error) {
-            return m.Service.VolumeServicer.Describe(
+        Action: deletedWaitAction(func() (interface{}, error) {
+            return m.Service.VolumeServicer.Describe(ctx,
                 &service.VolumeDescribeRequest{
                     DefaultRequestParams: defaultParams,
                     VolumeIds:            []string{d.Id()},
                 })
-        },
-        CompareFn: defaultInverseWaitCompareFunc(),
+        }),
+        CompareFn: notFoundWaitCompareFunc(),
     }
-    _, err = w.Wait()
+    _, err = w.Wait(ctx)
     if err != nil {
-        return fmt.Errorf("error wait for state image: %s", d.Id())
+        return fmt.Errorf("volume %s is not confirmed to be deleted: %w", d.Id(), err)
     }
 
     m.Log.Info(fmt.Sprintf("volume terminated: %s", d.Id()))
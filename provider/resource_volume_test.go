@@ -0,0 +1,31 @@
+package provider
+
+import (
+    "context"
+    "github.com/golang/mock/gomock"
+    "github.com/hashicorp/go-hclog"
+    "terraform-provider-m3/service"
+    smock "terraform-provider-m3/service/mock"
+    "testing"
+)
+
+func TestVolumeWaitResized(t *testing.T) {
+    ctl := gomock.NewController(t)
+    defer ctl.Finish()
+
+    // volume is reported in use with the previous size until backend starts modifying it
+    mockVolumeServicer := smock.NewMockVolumeServicer(ctl)
+    gomock.InOrder(
+        mockVolumeServicer.EXPECT().Describe(gomock.Any(), gomock.Any()).
+            Return(&service.Volume{VolumeID: "vol-1", State: service.InUseState, SizeLabel: 10}, nil),
+        mockVolumeServicer.EXPECT().Describe(gomock.Any(), gomock.Any()).
+            Return(&service.Volume{VolumeID: "vol-1", State: service.ModifyingVolumeState, SizeLabel: 20}, nil),
+        mockVolumeServicer.EXPECT().Describe(gomock.Any(), gomock.Any()).
+            Return(&service.Volume{VolumeID: "vol-1", State: service.InUseState, SizeLabel: 20}, nil),
+    )
+
+    m := newMeta(&service.Service{VolumeServicer: mockVolumeServicer}, nil, hclog.NewNullLogger())
+    if err := volumeWaitResized(context.Background(), m, &service.DefaultRequestParams{}, "vol-1", "i-1", 20); err != nil {
+        t.Fatal(err)
+    }
+}
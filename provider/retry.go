@@ -1,48 +1,188 @@
 package provider
 
 import (
+    "context"
     "errors"
+    "fmt"
+    "strings"
+    "terraform-provider-m3/service"
+    "terraform-provider-m3/utils"
     "time"
 )
 
+const (
+    // defaultWaitMinDelay is the delay after the first check of resource
+    defaultWaitMinDelay = time.Second
+    // defaultWaitMaxDelay limits the delay which grows after every check
+    defaultWaitMaxDelay = 30 * time.Second
+    // waitDelayFactor is the growth of delay between checks
+    waitDelayFactor = 1.5
+    // defaultUnknownStateGrace limits waiting in states which are neither pending, target nor failed
+    defaultUnknownStateGrace = time.Minute
+)
+
 type waitAction func() (interface{}, error)
 type waitCompareFunc func(error) bool
 
+// stateRefreshFunc returns resource with its state and the reason of state reported by backend
+type stateRefreshFunc func() (result interface{}, state string, reason string, err error)
+
+// failedWaitError is returned by wait action when resource will never be accepted, so waiting stops immediately
+type failedWaitError struct {
+    err error
+}
+
+func (e *failedWaitError) Error() string {
+    return e.err.Error()
+}
+
+func (e *failedWaitError) Unwrap() error {
+    return e.err
+}
+
 func defaultWaitCompareFunc() waitCompareFunc {
     return func(err error) bool {
         return err == nil
     }
 }
 
-func defaultInverseWaitCompareFunc() waitCompareFunc {
+// notFoundWaitCompareFunc accepts only the error of resource which is not found, so waiting lasts until it is deleted
+func notFoundWaitCompareFunc() waitCompareFunc {
     return func(err error) bool {
-        return err != nil
+        return errors.Is(err, service.ErrNotFound)
+    }
+}
+
+// deletedWaitAction checks resource by describe while it is deleted, errors which are neither not found
+// nor transient never change, so waiting stops on them immediately
+func deletedWaitAction(describe waitAction) waitAction {
+    return func() (interface{}, error) {
+        result, err := describe()
+        if err != nil && !errors.Is(err, service.ErrNotFound) && !service.IsTransient(err) {
+            return nil, &failedWaitError{err}
+        }
+        return result, err
     }
 }
 
+// wait checks resource by Action until CompareFn accepts its error. Resource is checked immediately,
+// then with delays growing from MinDelay to MaxDelay until Timeout or deadline of context
 type wait struct {
     CompareFn waitCompareFunc
     Action    waitAction
-    Attempts  int
-    Delay     int
+    // Timeout limits waiting in addition to deadline of context, it is not limited if zero
+    Timeout  time.Duration
+    MinDelay time.Duration
+    MaxDelay time.Duration
 }
 
-func (w wait) Wait() (interface{}, error) {
-    if w.Delay == 0 {
-        w.Delay = 60
+func (w wait) Wait(ctx context.Context) (interface{}, error) {
+    if w.MinDelay == 0 {
+        w.MinDelay = defaultWaitMinDelay
     }
-    if w.Attempts == 0 {
-        w.Attempts = 30
+    if w.MaxDelay == 0 {
+        w.MaxDelay = defaultWaitMaxDelay
+    }
+    if w.Timeout != 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+        defer cancel()
     }
 
-    for i := 1; i <= w.Attempts; i++ {
+    start := time.Now()
+    delay := w.MinDelay
+    for {
         result, err := w.Action()
         if w.CompareFn(err) {
             return result, nil
         }
+        var failed *failedWaitError
+        if errors.As(err, &failed) {
+            return nil, failed.err
+        }
+
+        // the last error describes observed state of resource, no error means that resource still exists
+        observed := "resource still exists"
+        if err != nil {
+            observed = err.Error()
+        }
+
+        select {
+        case <-ctx.Done():
+            if errors.Is(ctx.Err(), context.Canceled) {
+                return nil, fmt.Errorf("waiting is canceled, last observed: %s", observed)
+            }
+            return nil, fmt.Errorf("timeout after %s, last observed: %s", time.Since(start).Round(time.Second), observed)
+        case <-time.After(delay):
+        }
+
+        delay = time.Duration(float64(delay) * waitDelayFactor)
+        if delay > w.MaxDelay {
+            delay = w.MaxDelay
+        }
+    }
+}
+
+// stateWait waits until resource reaches one of Target states. Pending states are waited out until timeout,
+// while Failed states are never left for target ones, so waiting fails on them immediately with reason of backend.
+// Backend may report transitional states which are not known yet, so unknown states are waited out during
+// UnknownStateGrace only. Refresh errors which are not transient stop waiting immediately as well
+type stateWait struct {
+    // Resource describes waited resource in errors, e.g. "instance i-123"
+    Resource string
+    Pending  []string
+    Target   []string
+    Failed   []string
+    Refresh  stateRefreshFunc
+    Timeout  time.Duration
+    MinDelay time.Duration
+    MaxDelay time.Duration
+    // UnknownStateGrace is counted from the first of consecutive unknown states, defaultUnknownStateGrace if zero
+    UnknownStateGrace time.Duration
+}
 
-        time.Sleep(time.Duration(w.Delay) * time.Second)
+func (w stateWait) Wait(ctx context.Context) (interface{}, error) {
+    if w.UnknownStateGrace == 0 {
+        w.UnknownStateGrace = defaultUnknownStateGrace
     }
+    var unknownSince time.Time
+    return wait{
+        Action: func() (interface{}, error) {
+            result, state, reason, err := w.Refresh()
+            if err != nil {
+                // network, throttling and server errors do not tell anything about resource, so it is checked again
+                var failed *failedWaitError
+                if !errors.As(err, &failed) && !service.IsTransient(err) {
+                    return nil, &failedWaitError{err}
+                }
+                return nil, err
+            }
+            expected := strings.Join(w.Target, " or ")
+            switch {
+            case utils.ContainsString(state, w.Target):
+                return result, nil
+            case utils.ContainsString(state, w.Pending):
+                unknownSince = time.Time{}
+                return nil, fmt.Errorf("%s state is %s, expected %s", w.Resource, state, expected)
+            case utils.ContainsString(state, w.Failed):
+                if reason == "" {
+                    reason = "no reason is reported"
+                }
+                return nil, &failedWaitError{fmt.Errorf("%s is failed in state %s: %s", w.Resource, state, reason)}
+            }
 
-    return nil, errors.New("timeout")
+            err = fmt.Errorf("%s is in unexpected state %s, expected %s", w.Resource, state, expected)
+            if unknownSince.IsZero() {
+                unknownSince = time.Now()
+            }
+            if time.Since(unknownSince) >= w.UnknownStateGrace {
+                return nil, &failedWaitError{err}
+            }
+            return nil, err
+        },
+        CompareFn: defaultWaitCompareFunc(),
+        Timeout:   w.Timeout,
+        MinDelay:  w.MinDelay,
+        MaxDelay:  w.MaxDelay,
+    }.Wait(ctx)
 }
@@ -1,11 +1,16 @@
 package provider
 
 import (
+    "context"
     "errors"
     "github.com/golang/mock/gomock"
+    "net"
     "terraform-provider-m3/service"
     smock "terraform-provider-m3/service/mock"
+    "strings"
+    "syscall"
     "testing"
+    "time"
 )
 
 func TestWait_Wait(t *testing.T) {
@@ -14,6 +19,8 @@ func TestWait_Wait(t *testing.T) {
         TestCase     struct {
             Name            string
             WantErr         bool
+            // WantErrText is expected part of error message
+            WantErrText     string
             MockBehavior    MockBehavior
             WaitAction      func(*service.Service) waitAction
             WaitCompareFunc waitCompareFunc
@@ -27,31 +34,74 @@ func TestWait_Wait(t *testing.T) {
             WaitCompareFunc: defaultWaitCompareFunc(),
             WaitAction: func(s *service.Service) waitAction {
                 return func() (interface{}, error) {
-                    return s.KeypairServicer.Describe(nil)
+                    return s.KeypairServicer.Describe(context.Background(), nil)
                 }
             },
             MockBehavior: func(m *smock.MockKeypairServicer) {
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, nil)
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, errors.New("some error"))
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, errors.New("some error"))
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, nil)
             },
         },
 
         {
-            Name:            "Got an error if run out of attempts",
+            Name:            "Got an error with last observed state on timeout",
             WantErr:         true,
+            WantErrText:     "last observed: instance state is starting, expected running",
             WaitCompareFunc: defaultWaitCompareFunc(),
             WaitAction: func(s *service.Service) waitAction {
                 return func() (interface{}, error) {
-                    return s.KeypairServicer.Describe(nil)
+                    return s.KeypairServicer.Describe(context.Background(), nil)
                 }
             },
             MockBehavior: func(m *smock.MockKeypairServicer) {
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
-                m.EXPECT().Describe(nil).Return(nil, errors.New("some error"))
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, errors.New("instance state is starting, expected running")).MinTimes(2)
+            },
+        },
+
+        {
+            Name:            "Got an error if resource still exists on timeout",
+            WantErr:         true,
+            WantErrText:     "last observed: resource still exists",
+            WaitCompareFunc: notFoundWaitCompareFunc(),
+            WaitAction: func(s *service.Service) waitAction {
+                return func() (interface{}, error) {
+                    return s.KeypairServicer.Describe(context.Background(), nil)
+                }
+            },
+            MockBehavior: func(m *smock.MockKeypairServicer) {
+                m.EXPECT().Describe(gomock.Any(), nil).Return(&service.Keypair{}, nil).MinTimes(2)
+            },
+        },
+
+        {
+            Name:            "OK when resource is not found after transient errors",
+            WantErr:         false,
+            WaitCompareFunc: notFoundWaitCompareFunc(),
+            WaitAction: func(s *service.Service) waitAction {
+                return deletedWaitAction(func() (interface{}, error) {
+                    return s.KeypairServicer.Describe(context.Background(), nil)
+                })
+            },
+            MockBehavior: func(m *smock.MockKeypairServicer) {
+                m.EXPECT().Describe(gomock.Any(), nil).Return(&service.Keypair{}, nil)
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET})
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, &service.Error{Kind: service.ErrNotFound, Message: "keypair not found"})
+            },
+        },
+
+        {
+            Name:            "Got an error immediately if resource can not be described",
+            WantErr:         true,
+            WantErrText:     "permission denied for keypair",
+            WaitCompareFunc: notFoundWaitCompareFunc(),
+            WaitAction: func(s *service.Service) waitAction {
+                return deletedWaitAction(func() (interface{}, error) {
+                    return s.KeypairServicer.Describe(context.Background(), nil)
+                })
+            },
+            MockBehavior: func(m *smock.MockKeypairServicer) {
+                m.EXPECT().Describe(gomock.Any(), nil).Return(nil, &service.Error{Kind: service.ErrPermissionDenied, Message: "permission denied for keypair"}).Times(1)
             },
         },
     }
@@ -68,17 +118,124 @@ func TestWait_Wait(t *testing.T) {
             s.KeypairServicer = mockKeypairServicer
 
             w := wait{
-                Delay:     1,
-                Attempts:  5,
+                Timeout:   100 * time.Millisecond,
+                MinDelay:  10 * time.Millisecond,
+                MaxDelay:  20 * time.Millisecond,
                 Action:    testCase.WaitAction(&s),
                 CompareFn: testCase.WaitCompareFunc,
             }
-            _, err := w.Wait()
+            _, err := w.Wait(context.Background())
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
             }
+            if err != nil && !strings.Contains(err.Error(), testCase.WantErrText) {
+                t.Fatalf("got error '%v' instead of '%s'", err, testCase.WantErrText)
+            }
+
+        })
+    }
+}
 
+func TestStateWait_Wait(t *testing.T) {
+    type (
+        Observed struct {
+            State  string
+            Reason string
+            Err    error
+        }
+        TestCase struct {
+            Name    string
+            WantErr bool
+            // WantErrText is expected part of error message
+            WantErrText string
+            Observed    []Observed
+            // WantRefreshes is number of checks of resource before waiting is finished
+            WantRefreshes int
+            // Grace is the period of unexpected states, it is longer than timeout if zero
+            Grace time.Duration
+        }
+    )
+
+    testTable := []TestCase{
+        {
+            Name:          "OK after pending states and transient errors",
+            Observed:      []Observed{{Err: &service.Error{Kind: service.ErrServer, StatusCode: 502}}, {State: "starting"}, {State: "running"}},
+            WantRefreshes: 3,
+        },
+        {
+            Name:          "Got an error immediately if resource can not be described",
+            WantErr:       true,
+            WantErrText:   "access denied",
+            Observed:      []Observed{{Err: &service.Error{Kind: service.ErrPermissionDenied, StatusCode: 403, Message: "access denied"}}},
+            WantRefreshes: 1,
+        },
+        {
+            Name:          "Got an error with reason of backend immediately in failed state",
+            WantErr:       true,
+            WantErrText:   "instance i-1 is failed in state error: insufficient capacity",
+            Observed:      []Observed{{State: "starting"}, {State: "error", Reason: "insufficient capacity"}},
+            WantRefreshes: 2,
+        },
+        {
+            Name:          "OK after unexpected states",
+            Observed:      []Observed{{State: "pending"}, {State: "starting"}, {State: "running"}},
+            WantRefreshes: 3,
+        },
+        {
+            Name:        "Got an error with last observed unexpected state on timeout",
+            WantErr:     true,
+            WantErrText: "last observed: instance i-1 is in unexpected state stopped, expected running",
+            Observed:    []Observed{{State: "stopped"}},
+        },
+        {
+            Name:          "Got an error if unexpected state lasts longer than grace period",
+            WantErr:       true,
+            WantErrText:   "instance i-1 is in unexpected state stopped, expected running",
+            Observed:      []Observed{{State: "stopped"}, {State: "starting"}, {State: "stopped"}, {State: "stopped"}},
+            Grace:         5 * time.Millisecond,
+            WantRefreshes: 4,
+        },
+        {
+            Name:        "Got an error with last observed state on timeout",
+            WantErr:     true,
+            WantErrText: "last observed: instance i-1 state is starting, expected running",
+            Observed:    []Observed{{State: "starting"}},
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            refreshes := 0
+            w := stateWait{
+                Resource:          "instance i-1",
+                Pending:           []string{"starting"},
+                Target:            []string{"running"},
+                Failed:            []string{"error"},
+                Timeout:           100 * time.Millisecond,
+                MinDelay:          10 * time.Millisecond,
+                MaxDelay:          20 * time.Millisecond,
+                UnknownStateGrace: testCase.Grace,
+                Refresh: func() (interface{}, string, string, error) {
+                    observed := testCase.Observed[len(testCase.Observed)-1]
+                    if refreshes < len(testCase.Observed) {
+                        observed = testCase.Observed[refreshes]
+                    }
+                    refreshes++
+                    return observed.State, observed.State, observed.Reason, observed.Err
+                },
+            }
+            _, err := w.Wait(context.Background())
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatalf("unexpected error '%v'", err)
+            }
+            if err != nil && !strings.Contains(err.Error(), testCase.WantErrText) {
+                t.Fatalf("got error '%v' instead of '%s'", err, testCase.WantErrText)
+            }
+            if testCase.WantRefreshes != 0 && refreshes != testCase.WantRefreshes {
+                t.Fatalf("resource is checked %d times instead of %d", refreshes, testCase.WantRefreshes)
+            }
         })
     }
 }
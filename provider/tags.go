@@ -0,0 +1,21 @@
+package provider
+
+// mergeTags returns provider default tags overridden by resource tags with the same keys
+func mergeTags(defaultTags, tags map[string]interface{}) map[string]interface{} {
+    merged := make(map[string]interface{}, len(defaultTags)+len(tags))
+    for key, value := range defaultTags {
+        merged[key] = value
+    }
+    for key, value := range tags {
+        merged[key] = value
+    }
+    return merged
+}
+
+// defaultTags returns default tags of provider, meta is not available if provider is not configured yet
+func defaultTags(meta interface{}) map[string]interface{} {
+    if m, ok := meta.(*Meta); ok {
+        return m.DefaultTags
+    }
+    return nil
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// maxUserDataSize limits decoded user data of instance in bytes, it is the least limit of supported clouds
+const maxUserDataSize = 16 * 1024
+
+// validateUserData checks size of raw user data
+func validateUserData(v interface{}, k string) ([]string, []error) {
+    if size := len(v.(string)); size > maxUserDataSize {
+        return nil, []error{fmt.Errorf("expected %s to be at most %d bytes, got %d", k, maxUserDataSize, size)}
+    }
+    return nil, nil
+}
+
+// validateUserDataBase64 checks that user data is base64 encoded and checks size of decoded user data
+func validateUserDataBase64(v interface{}, k string) ([]string, []error) {
+    decoded, err := base64.StdEncoding.DecodeString(v.(string))
+    if err != nil {
+        return nil, []error{fmt.Errorf("expected %s to be base64 encoded: %s", k, err)}
+    }
+    return validateUserData(string(decoded), k)
+}
+
+// userDataHashSum is stored in state instead of user data, so changes of user data are detected without keeping
+// possibly sensitive content in state
+func userDataHashSum(v interface{}) string {
+    value := v.(string)
+    if value == "" {
+        return ""
+    }
+    sum := sha256.Sum256([]byte(value))
+    return hex.EncodeToString(sum[:])
+}
+
+// instanceUserData returns base64 encoded user data of run request, raw user data is encoded
+func instanceUserData(raw, encoded string) string {
+    if raw != "" {
+        return base64.StdEncoding.EncodeToString([]byte(raw))
+    }
+    return encoded
+}
+
+// resourceInstanceUserData returns base64 encoded user data of configuration. It is read from raw configuration,
+// because resource data contains only hash of user data once it is stored in state, e.g. when instances are added
+func resourceInstanceUserData(d *schema.ResourceData) string {
+    config := d.GetRawConfig()
+    value := func(key string) string {
+        if config.IsNull() {
+            return ""
+        }
+        v := config.GetAttr(key)
+        if v.IsNull() || !v.IsKnown() {
+            return ""
+        }
+        return v.AsString()
+    }
+    return instanceUserData(value("user_data"), value("user_data_base64"))
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+    "encoding/base64"
+    "strings"
+    "testing"
+)
+
+func TestValidateUserData(t *testing.T) {
+    limit := strings.Repeat("a", maxUserDataSize)
+    testTable := []struct {
+        Name     string
+        Validate func(interface{}, string) ([]string, []error)
+        Value    string
+        Valid    bool
+    }{
+        {Name: "raw", Validate: validateUserData, Value: "#!/bin/sh\necho hello", Valid: true},
+        {Name: "raw at limit", Validate: validateUserData, Value: limit, Valid: true},
+        {Name: "raw over limit", Validate: validateUserData, Value: limit + "a"},
+        {Name: "base64", Validate: validateUserDataBase64, Value: base64.StdEncoding.EncodeToString([]byte(limit)), Valid: true},
+        {Name: "base64 over limit", Validate: validateUserDataBase64, Value: base64.StdEncoding.EncodeToString([]byte(limit + "a"))},
+        {Name: "not base64", Validate: validateUserDataBase64, Value: "echo hello"},
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            if _, errs := testCase.Validate(testCase.Value, "user_data"); (len(errs) == 0) != testCase.Valid {
+                t.Fatalf("validation returned %v", errs)
+            }
+        })
+    }
+}
+
+func TestInstanceUserData(t *testing.T) {
+    encoded := base64.StdEncoding.EncodeToString([]byte("echo hello"))
+    if got := instanceUserData("echo hello", ""); got != encoded {
+        t.Fatalf("raw user data is encoded as %s instead of %s", got, encoded)
+    }
+    if got := instanceUserData("", encoded); got != encoded {
+        t.Fatalf("encoded user data is changed to %s", got)
+    }
+    if userDataHashSum("") != "" || userDataHashSum("echo hello") == userDataHashSum("echo bye") {
+        t.Fatal("hash sums of user data do not detect changes")
+    }
+}
@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -27,21 +27,21 @@ func NewDataChefService(t client.Transporter) *DataChefService {
     return &DataChefService{trans: t}
 }
 
-func (s *DataChefService) DataChefGetList(request *DefaultRequestParams) (*DataChef, error) {
+func (s *DataChefService) DataChefGetList(ctx context.Context, request *DefaultRequestParams) (*DataChef, error) {
     payload, err := s.trans.MakePayload(request, MethodGetChefProfiles)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     var dataChef DataChef
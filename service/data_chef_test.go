@@ -1,6 +1,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -52,7 +53,7 @@ func TestDataChefService_DataChefGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodGetChefProfiles).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -85,7 +86,7 @@ func TestDataChefService_DataChefGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodGetChefProfiles).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -115,7 +116,7 @@ func TestDataChefService_DataChefGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodGetChefProfiles).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -145,7 +146,7 @@ func TestDataChefService_DataChefGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodGetChefProfiles).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -161,7 +162,7 @@ func TestDataChefService_DataChefGetList(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.DataChefServicer.DataChefGetList(testCase.Request.(*DefaultRequestParams))
+            _, err := s.DataChefServicer.DataChefGetList(context.Background(), testCase.Request.(*DefaultRequestParams))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
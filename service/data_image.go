@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -15,15 +15,15 @@ func NewDataImageService(t client.Transporter) *DataImageService {
     return &DataImageService{trans: t}
 }
 
-func (s *DataImageService) DataImageGetList(request *DefaultRequestParams) (*[]Image, error) {
+func (s *DataImageService) DataImageGetList(ctx context.Context, request *DefaultRequestParams) (*[]Image, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeImage)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     images := make([]Image, 0, 32)
@@ -31,7 +31,7 @@ func (s *DataImageService) DataImageGetList(request *DefaultRequestParams) (*[]I
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -1,6 +1,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -57,7 +58,7 @@ func TestDataImageService_DataImageGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -90,7 +91,7 @@ func TestDataImageService_DataImageGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -120,7 +121,7 @@ func TestDataImageService_DataImageGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -150,7 +151,7 @@ func TestDataImageService_DataImageGetList(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -166,7 +167,7 @@ func TestDataImageService_DataImageGetList(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.DataImageServicer.DataImageGetList(testCase.Request.(*DefaultRequestParams))
+            _, err := s.DataImageServicer.DataImageGetList(context.Background(), testCase.Request.(*DefaultRequestParams))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -32,15 +32,15 @@ type DataItem struct {
     Options []DataOption `json:"options"`
 }
 
-func (s *DataPlacementParamsService) DataPlacementGetList(request *PlacementParamsRequest) (*[]DataItem, error) {
+func (s *DataPlacementParamsService) DataPlacementGetList(ctx context.Context, request *PlacementParamsRequest) (*[]DataItem, error) {
     payload, err := s.trans.MakePayload(request, MethodGetPlacementParameters)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     data := make([]DataItem, 0, 32)
@@ -48,7 +48,7 @@ func (s *DataPlacementParamsService) DataPlacementGetList(request *PlacementPara
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -0,0 +1,115 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "strings"
+    "terraform-provider-m3/client"
+)
+
+// Kinds of errors returned by Maestro3, check them with errors.Is
+var (
+    ErrNotFound         = errors.New("not found")
+    ErrConflict         = errors.New("conflict")
+    ErrValidation       = errors.New("validation failed")
+    ErrPermissionDenied = errors.New("permission denied")
+    ErrThrottled        = errors.New("throttled")
+    ErrServer           = errors.New("server error")
+)
+
+// Error is failed result of Maestro3 action or failed HTTP request, use errors.As to get status code
+type Error struct {
+    // Kind is one of Err* kinds or nil when error can not be classified
+    Kind       error
+    StatusCode int
+    Message    string
+}
+
+func (e *Error) Error() string {
+    return e.Message
+}
+
+func (e *Error) Unwrap() error {
+    return e.Kind
+}
+
+// IsTransient checks if error is caused by network, timeout, throttling or server failure, so the same request
+// may succeed later. Any other error is permanent
+func IsTransient(err error) bool {
+    if errors.Is(err, ErrThrottled) || errors.Is(err, ErrServer) || errors.Is(err, context.DeadlineExceeded) {
+        return true
+    }
+    var statusErr *client.StatusError
+    if errors.As(err, &statusErr) {
+        return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+    }
+    var netErr net.Error
+    return errors.As(err, &netErr)
+}
+
+// notFoundMessages contains texts of errors which Maestro3 returns for missing entities without 404 status code
+var notFoundMessages = []string{
+    "not found",
+    "no unique volume found",
+    "no unique image found",
+    "does not exist",
+}
+
+// errorKind classifies error by status code and, if code is not informative, by error text
+func errorKind(statusCode int, message string) error {
+    switch {
+    case statusCode == http.StatusNotFound:
+        return ErrNotFound
+    case statusCode == http.StatusConflict:
+        return ErrConflict
+    case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+        return ErrValidation
+    case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+        return ErrPermissionDenied
+    case statusCode == http.StatusTooManyRequests:
+        return ErrThrottled
+    case statusCode >= 500:
+        return ErrServer
+    }
+
+    lower := strings.ToLower(message)
+    for _, text := range notFoundMessages {
+        if strings.Contains(lower, text) {
+            return ErrNotFound
+        }
+    }
+    if strings.Contains(lower, "already exist") {
+        return ErrConflict
+    }
+    return nil
+}
+
+// resultError converts error of action result
+func resultError(result *client.M3RawResult) error {
+    return &Error{
+        Kind:       errorKind(result.StatusCode, result.Error),
+        StatusCode: result.StatusCode,
+        Message:    result.Error,
+    }
+}
+
+// transportError classifies HTTP status of failed request, other errors are returned as is
+func transportError(err error) error {
+    var statusErr *client.StatusError
+    if errors.As(err, &statusErr) {
+        return &Error{
+            Kind:       errorKind(statusErr.StatusCode, statusErr.Body),
+            StatusCode: statusErr.StatusCode,
+            Message:    err.Error(),
+        }
+    }
+    return err
+}
+
+// notFound is returned when entity is absent in successful describe result
+func notFound(format string, args ...interface{}) error {
+    return &Error{Kind: ErrNotFound, StatusCode: http.StatusNotFound, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "syscall"
+    "terraform-provider-m3/client"
+    "testing"
+)
+
+func TestResultError(t *testing.T) {
+    type TestCase struct {
+        Name   string
+        Result *client.M3RawResult
+        Want   error
+    }
+
+    testTable := []TestCase{
+        {
+            Name:   "OK not found by status code",
+            Result: &client.M3RawResult{StatusCode: 404, Error: "Instance i-1 is absent"},
+            Want:   ErrNotFound,
+        },
+
+        {
+            Name:   "OK not found by error text",
+            Result: &client.M3RawResult{StatusCode: 200, Error: "No unique volume found by volume ID vol-1"},
+            Want:   ErrNotFound,
+        },
+
+        {
+            Name:   "OK conflict",
+            Result: &client.M3RawResult{StatusCode: 409, Error: "Key already exists"},
+            Want:   ErrConflict,
+        },
+
+        {
+            Name:   "OK validation",
+            Result: &client.M3RawResult{StatusCode: 400, Error: "Invalid shape"},
+            Want:   ErrValidation,
+        },
+
+        {
+            Name:   "OK permission denied",
+            Result: &client.M3RawResult{StatusCode: 403, Error: "Access denied"},
+            Want:   ErrPermissionDenied,
+        },
+
+        {
+            Name:   "OK throttled",
+            Result: &client.M3RawResult{StatusCode: 429, Error: "Too many requests"},
+            Want:   ErrThrottled,
+        },
+
+        {
+            Name:   "OK server error",
+            Result: &client.M3RawResult{StatusCode: 502, Error: "Bad gateway"},
+            Want:   ErrServer,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            err := resultError(testCase.Result)
+
+            if !errors.Is(err, testCase.Want) {
+                t.Fatalf("got '%v' which is not %v", err, testCase.Want)
+            }
+            var serviceErr *Error
+            if !errors.As(err, &serviceErr) || serviceErr.StatusCode != testCase.Result.StatusCode {
+                t.Fatalf("status code is lost in '%v'", err)
+            }
+        })
+    }
+}
+
+func TestTransportError(t *testing.T) {
+    err := transportError(fmt.Errorf("request failed: %w", &client.StatusError{StatusCode: 401, Body: "unknown access key"}))
+    if !errors.Is(err, ErrPermissionDenied) {
+        t.Fatalf("got '%v' which is not %v", err, ErrPermissionDenied)
+    }
+
+    other := errors.New("connection refused")
+    if transportError(other) != other {
+        t.Fatal("unclassified error is changed")
+    }
+}
+
+func TestIsTransient(t *testing.T) {
+    type TestCase struct {
+        Name string
+        Err  error
+        Want bool
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK server error is transient",
+            Err:  &Error{Kind: ErrServer, StatusCode: 502},
+            Want: true,
+        },
+
+        {
+            Name: "OK throttling is transient",
+            Err:  &Error{Kind: ErrThrottled, StatusCode: 429},
+            Want: true,
+        },
+
+        {
+            Name: "OK permission denied is permanent",
+            Err:  &Error{Kind: ErrPermissionDenied, StatusCode: 403},
+            Want: false,
+        },
+
+        {
+            Name: "OK wrapped validation error is permanent",
+            Err:  fmt.Errorf("describe: %w", &Error{Kind: ErrValidation}),
+            Want: false,
+        },
+
+        {
+            Name: "OK unclassified service error is permanent",
+            Err:  &Error{Message: "instance is in unknown state"},
+            Want: false,
+        },
+
+        {
+            Name: "OK network error is transient",
+            Err:  fmt.Errorf("failed to process request: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+            Want: true,
+        },
+
+        {
+            Name: "OK exceeded deadline is transient",
+            Err:  fmt.Errorf("async requests are not completed: %w", context.DeadlineExceeded),
+            Want: true,
+        },
+
+        {
+            Name: "OK throttled request is transient",
+            Err:  fmt.Errorf("request failed: %w", &client.StatusError{StatusCode: 429}),
+            Want: true,
+        },
+
+        {
+            Name: "OK request failed on server is transient",
+            Err:  &client.StatusError{StatusCode: 503},
+            Want: true,
+        },
+
+        {
+            Name: "OK rejected request is permanent",
+            Err:  &client.StatusError{StatusCode: 400},
+            Want: false,
+        },
+
+        {
+            Name: "OK canceled context is permanent",
+            Err:  context.Canceled,
+            Want: false,
+        },
+
+        {
+            Name: "OK unknown error is permanent",
+            Err:  errors.New("can not decrypt response"),
+            Want: false,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            if got := IsTransient(testCase.Err); got != testCase.Want {
+                t.Fatalf("IsTransient('%v') = %v, want %v", testCase.Err, got, testCase.Want)
+            }
+        })
+    }
+}
@@ -1,15 +1,20 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
-    "strings"
     "terraform-provider-m3/client"
 )
 
 //AvailableImageState is for image in Available state
 var AvailableImageState = "Available"
+
+// PendingImageState is for image which is being created
+var PendingImageState = "Pending"
+
+// FailedImageState is for image which creation is failed
+var FailedImageState = "Failed"
 var InUseState = "in-use"
 
 // Image struct contains information about image
@@ -24,6 +29,7 @@ type Image struct {
     OsType      string `json:"osType"`
     ImageType   string `json:"imageType"`
     State       string `json:"imageState"`
+    StateReason string `json:"stateReason"`
     Cloud       string `json:"cloud"`
     Owner       string `json:"owner"`
 }
@@ -59,15 +65,15 @@ func NewImageService(t client.Transporter) *ImageService {
 }
 
 //Create is method to create image
-func (s *ImageService) Create(request *ImageCreateRequest) (*Image, error) {
+func (s *ImageService) Create(ctx context.Context, request *ImageCreateRequest) (*Image, error) {
     payload, err := s.trans.MakePayload(request, MethodCreateImage)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     images := make([]Image, 0, 2)
@@ -75,7 +81,7 @@ func (s *ImageService) Create(request *ImageCreateRequest) (*Image, error) {
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -92,24 +98,21 @@ func (s *ImageService) Create(request *ImageCreateRequest) (*Image, error) {
 }
 
 // Delete method to delete image
-func (s *ImageService) Delete(request *DeleteImageRequest) error {
+func (s *ImageService) Delete(ctx context.Context, request *DeleteImageRequest) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteImage)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        if strings.Contains(singleResult.Error, "no unique image found by image ID") {
-            return errors.New("404")
-        }
-        return fmt.Errorf("%+v", singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status == "SUCCESS" {
@@ -120,15 +123,15 @@ func (s *ImageService) Delete(request *DeleteImageRequest) error {
 }
 
 // Describe is method for describe image
-func (s *ImageService) Describe(request *ImageDescribeRequest) (*Image, error) {
+func (s *ImageService) Describe(ctx context.Context, request *ImageDescribeRequest) (*Image, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeImage)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     images := make([]Image, 0, 2)
@@ -145,14 +148,11 @@ func (s *ImageService) Describe(request *ImageDescribeRequest) (*Image, error) {
                 return &image, err
             }
         }
-        return nil, errors.New("404")
+        return nil, notFound("image %s not found", request.ImageIds[0])
     }
 
     if singleResult.Error != "" {
-        if strings.Contains(singleResult.Error, "No unique image found by image ID") {
-            return nil, errors.New("404")
-        }
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -57,7 +58,7 @@ func TestImageService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -90,7 +91,7 @@ func TestImageService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -120,7 +121,7 @@ func TestImageService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -150,7 +151,7 @@ func TestImageService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -166,7 +167,7 @@ func TestImageService_Create(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.ImageServicer.Create(testCase.Request.(*ImageCreateRequest))
+            _, err := s.ImageServicer.Create(context.Background(), testCase.Request.(*ImageCreateRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -225,7 +226,7 @@ func TestImageService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -258,7 +259,7 @@ func TestImageService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -288,7 +289,7 @@ func TestImageService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -318,7 +319,7 @@ func TestImageService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -334,7 +335,7 @@ func TestImageService_Delete(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.ImageServicer.Delete(testCase.Request.(*DeleteImageRequest))
+            err := s.ImageServicer.Delete(context.Background(), testCase.Request.(*DeleteImageRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -401,7 +402,7 @@ func TestImageService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -450,7 +451,7 @@ func TestImageService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -483,7 +484,7 @@ func TestImageService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -513,7 +514,7 @@ func TestImageService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -544,7 +545,7 @@ func TestImageService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeImage).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -560,7 +561,7 @@ func TestImageService_Describe(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.ImageServicer.Describe(testCase.Request.(*ImageDescribeRequest))
+            _, err := s.ImageServicer.Describe(context.Background(), testCase.Request.(*ImageDescribeRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -1,12 +1,13 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "fmt"
     "reflect"
+    "strings"
     "terraform-provider-m3/client"
-    "terraform-provider-m3/utils"
 )
 
 // InstanceStates contains possible instance states
@@ -16,14 +17,60 @@ var InstanceStates = struct {
     Stopped     string
     Running     string
     Terminating string
+    Terminated  string
     Cloning     string
+    Error       string
 }{
     Starting:    "starting",
     Stopping:    "stopping",
     Stopped:     "stopped",
     Running:     "running",
     Terminating: "terminating",
+    Terminated:  "terminated",
     Cloning:     "cloning",
+    Error:       "error",
+}
+
+// TerminationProtectionActions contains actions of termination protection management
+var TerminationProtectionActions = struct {
+    Enable  string
+    Disable string
+}{
+    Enable:  "ENABLE",
+    Disable: "DISABLE",
+}
+
+// TerminationProtectionClouds contains clouds which support termination protection of instances
+var TerminationProtectionClouds = []string{"AWS", "AZURE", "GOOGLE"}
+
+// IsTerminationProtectionSupported checks if cloud supports termination protection of instances
+func IsTerminationProtectionSupported(cloud string) bool {
+    for _, supported := range TerminationProtectionClouds {
+        if strings.EqualFold(supported, cloud) {
+            return true
+        }
+    }
+    return false
+}
+
+// ResizeClouds contains clouds which support change of instance shape,
+// the value tells if instance must be stopped before its shape is changed
+var ResizeClouds = map[string]bool{
+    "AWS":        true,
+    "AZURE":      false,
+    "GOOGLE":     true,
+    "OPEN_STACK": false,
+}
+
+// IsResizeSupported checks if cloud supports change of instance shape without replacement
+func IsResizeSupported(cloud string) bool {
+    _, ok := ResizeClouds[strings.ToUpper(cloud)]
+    return ok
+}
+
+// IsStopRequiredForResize checks if instance must be stopped before its shape is changed in cloud
+func IsStopRequiredForResize(cloud string) bool {
+    return ResizeClouds[strings.ToUpper(cloud)]
 }
 
 // It's mainly for isStateAllowed function, because I don't want to edit this something after I'll change InstanceStates
@@ -45,6 +92,7 @@ type Instance struct {
     TenantName        string                 `json:"tenant"`
     Region            string                 `json:"region"`
     State             string                 `json:"state"`
+    StateReason       string                 `json:"stateReason"`
     Created           string                 `json:"creationDate"`
     Architecture      string                 `json:"architecture"`
     Image             string                 `json:"imageId"`
@@ -54,8 +102,12 @@ type Instance struct {
     ChefEnabled       bool                   `json:"installChefClient"`
     InstanceChefUUID  string                 `json:"insanceChefUuid"`
     ChefProfile       string                 `json:"chefProfile"`
+    KeyName           string                 `json:"keyName"`
+    Owner             string                 `json:"owner"`
     AvailabilityZone  string                 `json:"availabilityZone"`
     ResourceGroup     string                 `json:"resourceGroup"`
+    StopAt            string                 `json:"stopAt"`
+    TerminateAt       string                 `json:"terminateAt"`
     VolumesIds        []string               `json:"volumesIds"`
     AdditionalData    map[string]interface{} `json:"additionalData"`
     Tags              []Tag                  `json:"tags"`
@@ -88,6 +140,10 @@ type InstanceRunRequest struct {
     LockedTermination bool                   `json:"lockedTermination"`
     AdditionalData    map[string]interface{} `json:"additionalData"`
     Tags              map[string]interface{} `json:"tags"`
+    // UserData is base64 encoded data which is passed to instance at boot
+    UserData string `json:"userData,omitempty"`
+    // StartupScript is file name of uploaded script which is run when instance boots
+    StartupScript string `json:"startupScript,omitempty"`
 }
 
 // StopAfter optional parameter for delayed stop
@@ -106,6 +162,35 @@ type InstanceTerminateRequest struct {
     InstanceID string `json:"instanceId"`
 }
 
+// InstanceTerminationProtectionRequest request to enable or disable termination protection of instance
+type InstanceTerminationProtectionRequest struct {
+    *DefaultRequestParams
+    InstanceID string `json:"instanceId"`
+    Action     string `json:"action"`
+}
+
+// InstancePowerRequest request to start, stop or reboot instance
+type InstancePowerRequest struct {
+    *DefaultRequestParams
+    InstanceID string `json:"instanceId"`
+}
+
+// InstanceResizeRequest request to change shape of instance
+type InstanceResizeRequest struct {
+    *DefaultRequestParams
+    InstanceID string `json:"instanceId"`
+    Shape      string `json:"shape"`
+}
+
+// InstanceExpirationRequest request to set times of delayed stop and terminate of instance in RFC3339 format,
+// empty time clears the delayed action
+type InstanceExpirationRequest struct {
+    *DefaultRequestParams
+    InstanceID  string `json:"instanceId"`
+    StopAt      string `json:"stopAt"`
+    TerminateAt string `json:"terminateAt"`
+}
+
 // InstanceDescribeRequest request to describe instance
 type InstanceDescribeRequest struct {
     *DefaultRequestParams
@@ -129,6 +214,7 @@ type InstanceDeleteTagsRequest struct {
     Id               string   `json:"instanceId"`
     AvailabilityZone string   `json:"availabilityZone"`
     ResourceGroup    string   `json:"resourceGroup"`
+    VolumeIds        []string `json:"volumeIds"`
     Tags             []string `json:"tags"`
 }
 
@@ -141,17 +227,17 @@ func NewInstancesService(t client.Transporter) *InstancesService {
     return &InstancesService{trans: t}
 }
 
-// Run method is needed to Run instance
-func (s *InstancesService) Run(request *InstanceRunRequest) (*Instance, error) {
+// Run method is needed to Run instances, it returns all instances launched by request
+func (s *InstancesService) Run(ctx context.Context, request *InstanceRunRequest) ([]*Instance, error) {
     body := *request
     payload, err := s.trans.MakePayload(&body, MethodRunInstance)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     instances := InstancesResultData{}
@@ -159,7 +245,7 @@ func (s *InstancesService) Run(request *InstanceRunRequest) (*Instance, error) {
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -167,59 +253,61 @@ func (s *InstancesService) Run(request *InstanceRunRequest) (*Instance, error) {
         if err != nil {
             return nil, err
         }
-        i := instances.Instances[0]
-        if i.State != InstanceStates.Starting {
-            return nil, fmt.Errorf("instance must be in '%v' state got '%v' instead",
-                InstanceStates.Starting, i.State)
+        if len(instances.Instances) == 0 {
+            return nil, errors.New("no instances in response")
         }
-        // success
-        instance := &Instance{
-            InstanceID:        i.InstanceID,
-            Cloud:             i.Cloud,
-            InstanceName:      i.InstanceName,
-            TenantName:        i.TenantName,
-            Region:            i.Region,
-            State:             i.State,
-            Created:           i.Created,
-            Architecture:      i.Architecture,
-            Image:             i.Image,
-            Shape:             i.Shape,
-            PrivateIP:         i.PrivateIP,
-            LockedTermination: request.LockedTermination,
-            ChefEnabled:       request.ChefEnabled,
-            InstanceChefUUID:  request.InstanceChefUUID,
-            ChefProfile:       request.ChefProfile,
-            AdditionalData:    request.AdditionalData,
-            Tags:              i.Tags,
+
+        // launched instances are returned together with the error, so they can be tracked and terminated
+        result := make([]*Instance, 0, len(instances.Instances))
+        for _, i := range instances.Instances {
+            if err == nil && i.State != InstanceStates.Starting && i.State != InstanceStates.Cloning {
+                err = fmt.Errorf("instance %s must be in '%v' state got '%v' instead",
+                    i.InstanceID, InstanceStates.Starting, i.State)
+            }
+            instance := &Instance{
+                InstanceID:        i.InstanceID,
+                Cloud:             i.Cloud,
+                InstanceName:      i.InstanceName,
+                TenantName:        i.TenantName,
+                Region:            i.Region,
+                State:             i.State,
+                Created:           i.Created,
+                Architecture:      i.Architecture,
+                Image:             i.Image,
+                Shape:             i.Shape,
+                PrivateIP:         i.PrivateIP,
+                LockedTermination: request.LockedTermination,
+                ChefEnabled:       request.ChefEnabled,
+                InstanceChefUUID:  request.InstanceChefUUID,
+                ChefProfile:       request.ChefProfile,
+                AdditionalData:    request.AdditionalData,
+                Tags:              i.Tags,
+            }
+            result = append(result, instance)
         }
-        instance.LockedTermination = instance.LockedTermination && utils.Contains(instance.Cloud, []interface{}{"AWS", "AZURE", "GOOGLE"})
 
-        return instance, err
+        return result, err
 
     }
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
 // Terminate method is used to terminate instance
-func (s *InstancesService) Terminate(request *InstanceTerminateRequest) error {
+func (s *InstancesService) Terminate(ctx context.Context, request *InstanceTerminateRequest) error {
     payload, err := s.trans.MakePayload(request, MethodTerminateInstance)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        // Somehow there's no instance, probably someone terminated it from web UI
-        if singleResult.StatusCode == 404 {
-            return errors.New("404")
-        }
-        return fmt.Errorf("%+v", singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status != "" {
@@ -230,97 +318,145 @@ func (s *InstancesService) Terminate(request *InstanceTerminateRequest) error {
 }
 
 //Describe method is used to describe instance
-func (s *InstancesService) Describe(request *InstanceDescribeRequest) (*Instance, error) {
+func (s *InstancesService) Describe(ctx context.Context, request *InstanceDescribeRequest) (*Instance, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeInstance)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
-    instances := InstancesResultData{}
+    return describedInstance(r.Results[0], request.InstanceIds)
+}
 
-    singleResult := r.Results[0]
+// List describes every instance of request by separate action of one batch request,
+// instances which are not found are omitted
+func (s *InstancesService) List(ctx context.Context, request *InstanceDescribeRequest) ([]*Instance, error) {
+    payloads := make([]*client.DefaultPayload, 0, len(request.InstanceIds))
+    for _, id := range request.InstanceIds {
+        payload, err := s.trans.MakePayload(&InstanceDescribeRequest{
+            DefaultRequestParams: request.DefaultRequestParams,
+            InstanceIds:          []string{id},
+        }, MethodDescribeInstance)
+        if err != nil {
+            return nil, err
+        }
+        payloads = append(payloads, payload)
+    }
+    if len(payloads) == 0 {
+        return nil, nil
+    }
 
-    if singleResult.Data != "" {
-        err = json.Unmarshal([]byte(singleResult.Data), &instances)
+    r, err := s.trans.DoBatch(ctx, payloads)
+    if err != nil {
+        return nil, transportError(err)
+    }
+
+    instances := make([]*Instance, 0, len(payloads))
+    for i, result := range r.Results {
+        instance, err := describedInstance(result, []string{request.InstanceIds[i]})
+        if errors.Is(err, ErrNotFound) {
+            continue
+        }
+        if err != nil {
+            return nil, err
+        }
+        instances = append(instances, instance)
+    }
+    return instances, nil
+}
+
+// describedInstance returns the first instance of describe result
+func describedInstance(result *client.M3RawResult, ids []string) (*Instance, error) {
+    instances := InstancesResultData{}
+
+    if result.Data != "" {
+        err := json.Unmarshal([]byte(result.Data), &instances)
         if err != nil {
             return nil, err
         }
         if len(instances.Instances) > 0 {
-            i := instances.Instances[0]
             // success
-            instance := &Instance{
-                InstanceID:       i.InstanceID,
-                Cloud:            i.Cloud,
-                InstanceName:     i.InstanceName,
-                TenantName:       i.TenantName,
-                Region:           i.Region,
-                State:            i.State,
-                Created:          i.Created,
-                Architecture:     i.Architecture,
-                Image:            i.Image,
-                Shape:            i.Shape,
-                PrivateIP:        i.PrivateIP,
-                AvailabilityZone: i.AvailabilityZone,
-                ResourceGroup:    i.ResourceGroup,
-                VolumesIds:       i.VolumesIds,
-                Tags:             i.Tags,
-            }
-
-            return instance, err
+            instance := instances.Instances[0]
+            return &instance, nil
         }
 
         // Somehow there's no instance, probably someone terminated it from web UI
-        return nil, errors.New("404")
+        return nil, notFound("instance %s not found", strings.Join(ids, ", "))
 
     }
 
-    if singleResult.Error != "" {
-        // Somehow there's no instance, probably someone terminated it from web UI
-        if singleResult.StatusCode == 404 {
-            return nil, errors.New("404")
-        }
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+    if result.Error != "" {
+        return nil, resultError(result)
     }
 
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *InstancesService) UnlockTermination(request *InstanceTerminateRequest) error {
-    body := struct {
-        InstanceTerminateRequest
-        Action string `json:"action"`
-    }{
-        InstanceTerminateRequest: *(request),
-        Action:                   "DISABLE",
+// ManageTerminationProtection method is used to enable or disable termination protection of instance
+func (s *InstancesService) ManageTerminationProtection(ctx context.Context, request *InstanceTerminationProtectionRequest) error {
+    payload, err := s.trans.MakePayload(request, MethodTerminationProtection)
+    if err != nil {
+        return err
     }
 
-    payload, err := s.trans.MakePayload(body, MethodTerminationProtection)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
+    }
+
+    if r.Results[0].Error != "" {
+        return resultError(r.Results[0])
     }
 
-    _, err = s.trans.Do(payload)
-    return err
+    return nil
+}
+
+// Start method is used to start stopped instance
+func (s *InstancesService) Start(ctx context.Context, request *InstancePowerRequest) error {
+    return s.power(ctx, request, MethodStartInstance)
+}
+
+// Stop method is used to stop running instance
+func (s *InstancesService) Stop(ctx context.Context, request *InstancePowerRequest) error {
+    return s.power(ctx, request, MethodStopInstance)
 }
 
-func (s *InstancesService) UpdateTags(request *InstanceUpdateTagsRequest) error {
+// Reboot method is used to reboot running instance
+func (s *InstancesService) Reboot(ctx context.Context, request *InstancePowerRequest) error {
+    return s.power(ctx, request, MethodRebootInstance)
+}
+
+// Resize method is used to change shape of instance, the instance keeps its ID and volumes
+func (s *InstancesService) Resize(ctx context.Context, request *InstanceResizeRequest) error {
+    return action(ctx, s.trans, request, MethodResizeInstance)
+}
+
+// ManageExpiration method is used to change or clear delayed stop and terminate of instance
+func (s *InstancesService) ManageExpiration(ctx context.Context, request *InstanceExpirationRequest) error {
+    return action(ctx, s.trans, request, MethodManageExpiration)
+}
+
+func (s *InstancesService) power(ctx context.Context, request *InstancePowerRequest, method string) error {
+    return action(ctx, s.trans, request, method)
+}
+
+func (s *InstancesService) UpdateTags(ctx context.Context, request *InstanceUpdateTagsRequest) error {
     payload, err := s.trans.MakePayload(request, MethodUpdateTags)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     if r.Results[0].Error != "" {
-        return errors.New(r.Results[0].Error)
+        return resultError(r.Results[0])
     }
 
     if r.Results[0].Data == "" {
@@ -330,19 +466,19 @@ func (s *InstancesService) UpdateTags(request *InstanceUpdateTagsRequest) error
     return nil
 }
 
-func (s *InstancesService) DeleteTags(request *InstanceDeleteTagsRequest) error {
+func (s *InstancesService) DeleteTags(ctx context.Context, request *InstanceDeleteTagsRequest) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteTags)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     if r.Results[0].Error != "" {
-        return errors.New(r.Results[0].Error)
+        return resultError(r.Results[0])
     }
 
     if r.Results[0].Data == "" {
@@ -1,9 +1,11 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
+    "strings"
     "terraform-provider-m3/client"
     cmock "terraform-provider-m3/client/mock"
     "testing"
@@ -17,6 +19,8 @@ func TestInstancesService_Run(t *testing.T) {
         WantErr      bool
         DoResponse   func() *client.M3BatchResult
         MockBehavior MockBehavior
+        // WantCount is number of instances expected in result, launched instances are returned with error too
+        WantCount int
     }
 
     testTable := []TestCase{
@@ -25,6 +29,8 @@ func TestInstancesService_Run(t *testing.T) {
 
             WantErr: false,
 
+            WantCount: 1,
+
             Request: &InstanceRunRequest{},
 
             DoResponse: func() *client.M3BatchResult {
@@ -70,7 +76,79 @@ func TestInstancesService_Run(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "OK with several instances",
+
+            WantErr: false,
+
+            WantCount: 2,
+
+            Request: &InstanceRunRequest{InstancesCount: 2},
+
+            DoResponse: func() *client.M3BatchResult {
+                instances := InstancesResultData{
+                    Instances: []Instance{
+                        {InstanceID: "1", Cloud: "AWS", State: "starting"},
+                        {InstanceID: "2", Cloud: "AWS", State: "starting"},
+                    },
+                }
+                data, _ := json.Marshal(instances)
+
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "SUCCESS",
+                    Error:  "",
+                    Data:   string(data),
+                }
+
+                return &client.M3BatchResult{
+                    Results: []*client.M3RawResult{raw},
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if some of instances not in starting state",
+
+            WantErr: true,
+
+            WantCount: 2,
+
+            Request: &InstanceRunRequest{InstancesCount: 2},
+
+            DoResponse: func() *client.M3BatchResult {
+                instances := InstancesResultData{
+                    Instances: []Instance{
+                        {InstanceID: "1", Cloud: "AWS", State: "starting"},
+                        {InstanceID: "2", Cloud: "AWS", State: "terminating"},
+                    },
+                }
+                data, _ := json.Marshal(instances)
+
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "SUCCESS",
+                    Error:  "",
+                    Data:   string(data),
+                }
+
+                return &client.M3BatchResult{
+                    Results: []*client.M3RawResult{raw},
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -123,7 +201,7 @@ func TestInstancesService_Run(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -156,7 +234,7 @@ func TestInstancesService_Run(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -186,7 +264,7 @@ func TestInstancesService_Run(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -216,7 +294,7 @@ func TestInstancesService_Run(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodRunInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -232,11 +310,14 @@ func TestInstancesService_Run(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.InstanceServicer.Run(testCase.Request.(*InstanceRunRequest))
+            instances, err := s.InstanceServicer.Run(context.Background(), testCase.Request.(*InstanceRunRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
             }
+            if len(instances) != testCase.WantCount {
+                t.Fatalf("got %d instances instead of %d", len(instances), testCase.WantCount)
+            }
 
         })
     }
@@ -281,7 +362,7 @@ func TestInstancesService_Terminate(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodTerminateInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -312,7 +393,7 @@ func TestInstancesService_Terminate(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodTerminateInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -345,7 +426,7 @@ func TestInstancesService_Terminate(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodTerminateInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -375,7 +456,7 @@ func TestInstancesService_Terminate(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodTerminateInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -405,7 +486,7 @@ func TestInstancesService_Terminate(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodTerminateInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -421,7 +502,7 @@ func TestInstancesService_Terminate(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.InstanceServicer.Terminate(testCase.Request.(*InstanceTerminateRequest))
+            err := s.InstanceServicer.Terminate(context.Background(), testCase.Request.(*InstanceTerminateRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -432,6 +513,441 @@ func TestInstancesService_Terminate(t *testing.T) {
 
 }
 
+func TestInstancesService_Power(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantErr: false,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "SUCCESS",
+                    Error:  "",
+                    Data:   "",
+                }
+
+                result := make([]*client.M3RawResult, 0, 2)
+
+                result = append(result, raw)
+
+                return &client.M3BatchResult{
+                    Results: result,
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error contain 'Instance is not in running state'",
+
+            WantErr: true,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "SUCCESS",
+                    Error:  "Instance is not in running state",
+                    Data:   "",
+                }
+
+                result := make([]*client.M3RawResult, 0, 2)
+
+                result = append(result, raw)
+
+                return &client.M3BatchResult{
+                    Results: result,
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error on during make payload",
+
+            WantErr: true,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+                return nil
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, errors.New("some error"))
+            },
+        },
+
+        {
+            Name: "Got error on during DO",
+
+            WantErr: true,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+                return nil
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
+            },
+        },
+
+        {
+            Name: "Got error if error in response not empty",
+
+            WantErr: true,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "FAIL",
+                    Error:  "some error",
+                    Data:   "",
+                }
+
+                result := make([]*client.M3RawResult, 0, 2)
+
+                result = append(result, raw)
+
+                return &client.M3BatchResult{
+                    Results: result,
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if neither result nor error in response",
+
+            WantErr: true,
+
+            Request: &InstancePowerRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "",
+                    Error:  "",
+                    Data:   "",
+                }
+
+                result := make([]*client.M3RawResult, 0, 2)
+
+                result = append(result, raw)
+
+                return &client.M3BatchResult{
+                    Results: result,
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, method string, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, method).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+    }
+
+    actions := map[string]func(InstanceServicer, context.Context, *InstancePowerRequest) error{
+        MethodStartInstance:  InstanceServicer.Start,
+        MethodStopInstance:   InstanceServicer.Stop,
+        MethodRebootInstance: InstanceServicer.Reboot,
+    }
+
+    for method, action := range actions {
+        for _, testCase := range testTable {
+            t.Run(method+"/"+testCase.Name, func(t *testing.T) {
+                ctl := gomock.NewController(t)
+                defer ctl.Finish()
+
+                mockTransporter := cmock.NewMockTransporter(ctl)
+                testCase.MockBehavior(mockTransporter, testCase.Request, method, testCase.DoResponse())
+
+                c := &client.Client{Transporter: mockTransporter}
+                s := NewService(c)
+
+                err := action(s.InstanceServicer, context.Background(), testCase.Request.(*InstancePowerRequest))
+
+                if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                    t.Fatal()
+                }
+            })
+        }
+    }
+
+}
+
+func TestInstancesService_Resize(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    response := func(status, err string) func() *client.M3BatchResult {
+        return func() *client.M3BatchResult {
+            raw := &client.M3RawResult{
+                ID:     "123456789",
+                Status: status,
+                Error:  err,
+                Data:   "",
+            }
+
+            return &client.M3BatchResult{
+                Results: []*client.M3RawResult{raw},
+            }
+        }
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantErr: false,
+
+            Request: &InstanceResizeRequest{InstanceID: "i-1", Shape: "LARGE"},
+
+            DoResponse: response("SUCCESS", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error contain 'Instance must be stopped'",
+
+            WantErr: true,
+
+            Request: &InstanceResizeRequest{InstanceID: "i-1", Shape: "LARGE"},
+
+            DoResponse: response("FAILED", "Instance must be stopped"),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error on during DO",
+
+            WantErr: true,
+
+            Request: &InstanceResizeRequest{InstanceID: "i-1", Shape: "LARGE"},
+
+            DoResponse: func() *client.M3BatchResult {
+                return nil
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
+            },
+        },
+
+        {
+            Name: "Got error if neither result nor error in response",
+
+            WantErr: true,
+
+            Request: &InstanceResizeRequest{InstanceID: "i-1", Shape: "LARGE"},
+
+            DoResponse: response("", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeInstance).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.Request, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            err := s.InstanceServicer.Resize(context.Background(), testCase.Request.(*InstanceResizeRequest))
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal()
+            }
+
+        })
+    }
+
+}
+
+func TestInstancesService_ManageExpiration(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    response := func(status, err string) func() *client.M3BatchResult {
+        return func() *client.M3BatchResult {
+            raw := &client.M3RawResult{
+                ID:     "123456789",
+                Status: status,
+                Error:  err,
+                Data:   "",
+            }
+
+            return &client.M3BatchResult{
+                Results: []*client.M3RawResult{raw},
+            }
+        }
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantErr: false,
+
+            Request: &InstanceExpirationRequest{InstanceID: "i-1", StopAt: "2030-01-02T15:04:05Z"},
+
+            DoResponse: response("SUCCESS", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodManageExpiration).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error contain 'Time is in the past'",
+
+            WantErr: true,
+
+            Request: &InstanceExpirationRequest{InstanceID: "i-1", StopAt: "2030-01-02T15:04:05Z"},
+
+            DoResponse: response("FAILED", "Time is in the past"),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodManageExpiration).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error on during DO",
+
+            WantErr: true,
+
+            Request: &InstanceExpirationRequest{InstanceID: "i-1", StopAt: "2030-01-02T15:04:05Z"},
+
+            DoResponse: func() *client.M3BatchResult {
+                return nil
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodManageExpiration).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
+            },
+        },
+
+        {
+            Name: "Got error if neither result nor error in response",
+
+            WantErr: true,
+
+            Request: &InstanceExpirationRequest{InstanceID: "i-1", StopAt: "2030-01-02T15:04:05Z"},
+
+            DoResponse: response("", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodManageExpiration).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.Request, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            err := s.InstanceServicer.ManageExpiration(context.Background(), testCase.Request.(*InstanceExpirationRequest))
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal()
+            }
+
+        })
+    }
+
+}
+
+func TestIsResizeSupported(t *testing.T) {
+    testTable := map[string][2]bool{
+        "AWS":        {true, true},
+        "azure":      {true, false},
+        "OPEN_STACK": {true, false},
+        "NUTANIX":    {false, false},
+    }
+    for cloud, want := range testTable {
+        if got := IsResizeSupported(cloud); got != want[0] {
+            t.Errorf("IsResizeSupported(%s) = %v, want %v", cloud, got, want[0])
+        }
+        if got := IsStopRequiredForResize(cloud); got != want[1] {
+            t.Errorf("IsStopRequiredForResize(%s) = %v, want %v", cloud, got, want[1])
+        }
+    }
+}
+
 func TestInstancesService_Describe(t *testing.T) {
     type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
     type TestCase struct {
@@ -494,7 +1010,7 @@ func TestInstancesService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -527,7 +1043,7 @@ func TestInstancesService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -557,7 +1073,7 @@ func TestInstancesService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -587,7 +1103,7 @@ func TestInstancesService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -617,7 +1133,7 @@ func TestInstancesService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeInstance).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -633,7 +1149,7 @@ func TestInstancesService_Describe(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.InstanceServicer.Describe(testCase.Request.(*InstanceDescribeRequest))
+            _, err := s.InstanceServicer.Describe(context.Background(), testCase.Request.(*InstanceDescribeRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -644,7 +1160,106 @@ func TestInstancesService_Describe(t *testing.T) {
 
 }
 
-func TestInstancesService_UnlockTermination(t *testing.T) {
+func TestInstancesService_List(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Name         string
+        WantErr      bool
+        InstanceIds  []string
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+        // WantIds are IDs of listed instances
+        WantIds []string
+    }
+
+    described := func(ids ...string) *client.M3RawResult {
+        instances := InstancesResultData{Instances: []Instance{}}
+        for _, id := range ids {
+            instances.Instances = append(instances.Instances, Instance{InstanceID: id, State: "running"})
+        }
+        data, _ := json.Marshal(instances)
+        return &client.M3RawResult{Status: "SUCCESS", Data: string(data)}
+    }
+    batched := func(m *cmock.MockTransporter, DoResponse *client.M3BatchResult) {
+        m.EXPECT().MakePayload(gomock.Any(), MethodDescribeInstance).Return(&client.DefaultPayload{}, nil).Times(len(DoResponse.Results))
+        m.EXPECT().DoBatch(gomock.Any(), gomock.Len(len(DoResponse.Results))).Return(DoResponse, nil)
+    }
+
+    testTable := []TestCase{
+        {
+            Name:        "OK with instances which are not found omitted",
+            InstanceIds: []string{"i-1", "i-2", "i-3", "i-4"},
+            DoResponse: func() *client.M3BatchResult {
+                return &client.M3BatchResult{Results: []*client.M3RawResult{
+                    described("i-1"),
+                    described(),
+                    {Status: "FAILED", Error: "Instance i-3 not found", StatusCode: 404},
+                    described("i-4"),
+                }}
+            },
+            MockBehavior: batched,
+            WantIds:      []string{"i-1", "i-4"},
+        },
+
+        {
+            Name:         "OK without request if there are no instances",
+            DoResponse:   func() *client.M3BatchResult { return &client.M3BatchResult{} },
+            MockBehavior: func(m *cmock.MockTransporter, DoResponse *client.M3BatchResult) {},
+        },
+
+        {
+            Name:        "Got error if some of instances can not be described",
+            WantErr:     true,
+            InstanceIds: []string{"i-1", "i-2"},
+            DoResponse: func() *client.M3BatchResult {
+                return &client.M3BatchResult{Results: []*client.M3RawResult{
+                    described("i-1"),
+                    {Status: "FAILED", Error: "Permission denied", StatusCode: 403},
+                }}
+            },
+            MockBehavior: batched,
+        },
+
+        {
+            Name:        "Got error if batch request failed",
+            WantErr:     true,
+            InstanceIds: []string{"i-1"},
+            DoResponse:  func() *client.M3BatchResult { return nil },
+            MockBehavior: func(m *cmock.MockTransporter, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(gomock.Any(), MethodDescribeInstance).Return(&client.DefaultPayload{}, nil)
+                m.EXPECT().DoBatch(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            instances, err := s.InstanceServicer.List(context.Background(), &InstanceDescribeRequest{InstanceIds: testCase.InstanceIds})
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatalf("unexpected error '%v'", err)
+            }
+            ids := make([]string, 0, len(instances))
+            for _, instance := range instances {
+                ids = append(ids, instance.InstanceID)
+            }
+            if strings.Join(ids, ",") != strings.Join(testCase.WantIds, ",") {
+                t.Fatalf("got instances %v instead of %v", ids, testCase.WantIds)
+            }
+        })
+    }
+}
+
+func TestInstancesService_ManageTerminationProtection(t *testing.T) {
     type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
     type TestCase struct {
         Request      interface{}
@@ -660,7 +1275,7 @@ func TestInstancesService_UnlockTermination(t *testing.T) {
 
             WantErr: false,
 
-            Request: &InstanceTerminateRequest{},
+            Request: &InstanceTerminationProtectionRequest{Action: TerminationProtectionActions.Disable},
 
             DoResponse: func() *client.M3BatchResult {
 
@@ -681,14 +1296,60 @@ func TestInstancesService_UnlockTermination(t *testing.T) {
             },
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
-                m.EXPECT().MakePayload(struct {
-                    InstanceTerminateRequest
-                    Action string `json:"action"`
-                }{
-                    InstanceTerminateRequest: *(request.(*InstanceTerminateRequest)),
-                    Action:                   "DISABLE",
-                }, MethodTerminationProtection).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().MakePayload(request, MethodTerminationProtection).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "OK enable",
+
+            WantErr: false,
+
+            Request: &InstanceTerminationProtectionRequest{Action: TerminationProtectionActions.Enable},
+
+            DoResponse: func() *client.M3BatchResult {
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "SUCCESS",
+                    Error:  "",
+                    Data:   "",
+                }
+
+                return &client.M3BatchResult{
+                    Results: []*client.M3RawResult{raw},
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodTerminationProtection).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error in response not empty",
+
+            WantErr: true,
+
+            Request: &InstanceTerminationProtectionRequest{Action: TerminationProtectionActions.Enable},
+
+            DoResponse: func() *client.M3BatchResult {
+                raw := &client.M3RawResult{
+                    ID:     "123456789",
+                    Status: "FAIL",
+                    Error:  "termination protection is not supported",
+                    Data:   "",
+                }
+
+                return &client.M3BatchResult{
+                    Results: []*client.M3RawResult{raw},
+                }
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodTerminationProtection).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -697,20 +1358,14 @@ func TestInstancesService_UnlockTermination(t *testing.T) {
 
             WantErr: true,
 
-            Request: &InstanceTerminateRequest{},
+            Request: &InstanceTerminationProtectionRequest{Action: TerminationProtectionActions.Disable},
 
             DoResponse: func() *client.M3BatchResult {
                 return nil
             },
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
-                m.EXPECT().MakePayload(struct {
-                    InstanceTerminateRequest
-                    Action string `json:"action"`
-                }{
-                    InstanceTerminateRequest: *(request.(*InstanceTerminateRequest)),
-                    Action:                   "DISABLE",
-                }, MethodTerminationProtection).Return(nil, errors.New("some error"))
+                m.EXPECT().MakePayload(request, MethodTerminationProtection).Return(nil, errors.New("some error"))
             },
         },
 
@@ -719,21 +1374,15 @@ func TestInstancesService_UnlockTermination(t *testing.T) {
 
             WantErr: true,
 
-            Request: &InstanceTerminateRequest{},
+            Request: &InstanceTerminationProtectionRequest{Action: TerminationProtectionActions.Disable},
 
             DoResponse: func() *client.M3BatchResult {
                 return nil
             },
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
-                m.EXPECT().MakePayload(struct {
-                    InstanceTerminateRequest
-                    Action string `json:"action"`
-                }{
-                    InstanceTerminateRequest: *(request.(*InstanceTerminateRequest)),
-                    Action:                   "DISABLE",
-                }, MethodTerminationProtection).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().MakePayload(request, MethodTerminationProtection).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
     }
@@ -749,7 +1398,7 @@ func TestInstancesService_UnlockTermination(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.InstanceServicer.UnlockTermination(testCase.Request.(*InstanceTerminateRequest))
+            err := s.InstanceServicer.ManageTerminationProtection(context.Background(), testCase.Request.(*InstanceTerminationProtectionRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -798,7 +1447,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodUpdateTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -829,7 +1478,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodUpdateTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -862,7 +1511,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodUpdateTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -892,7 +1541,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodUpdateTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -922,7 +1571,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodUpdateTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -938,7 +1587,7 @@ func TestInstancesService_UpdateTags(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.InstanceServicer.UpdateTags(testCase.Request.(*InstanceUpdateTagsRequest))
+            err := s.InstanceServicer.UpdateTags(context.Background(), testCase.Request.(*InstanceUpdateTagsRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -987,7 +1636,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -1018,7 +1667,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -1051,7 +1700,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -1081,7 +1730,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -1111,7 +1760,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteTags).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -1127,7 +1776,7 @@ func TestInstancesService_DeleteTags(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.InstanceServicer.DeleteTags(testCase.Request.(*InstanceDeleteTagsRequest))
+            err := s.InstanceServicer.DeleteTags(context.Background(), testCase.Request.(*InstanceDeleteTagsRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
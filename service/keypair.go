@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -84,22 +84,22 @@ func NewKeypairService(t client.Transporter) *KeypairService {
     return &KeypairService{trans: t}
 }
 
-func (s *KeypairService) Create(request *KeypairRequest) (*Keypair, error) {
+func (s *KeypairService) Create(ctx context.Context, request *KeypairRequest) (*Keypair, error) {
     payload, err := s.trans.MakePayload(request, MethodCreateKeypair)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     singleResult := r.Results[0]
     keypair := &Keypair{}
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -115,15 +115,15 @@ func (s *KeypairService) Create(request *KeypairRequest) (*Keypair, error) {
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *KeypairService) Describe(request *KeypairRequest) (*Keypair, error) {
+func (s *KeypairService) Describe(ctx context.Context, request *KeypairRequest) (*Keypair, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeKeypair)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     keypairs := make([]Keypair, 0, 2)
@@ -131,7 +131,7 @@ func (s *KeypairService) Describe(request *KeypairRequest) (*Keypair, error) {
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, errors.New(singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -146,26 +146,26 @@ func (s *KeypairService) Describe(request *KeypairRequest) (*Keypair, error) {
             }
         }
 
-        return nil, errors.New("404")
+        return nil, notFound("keypair %s not found", request.Name)
     }
 
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *KeypairService) Delete(request *KeypairRequest) error {
+func (s *KeypairService) Delete(ctx context.Context, request *KeypairRequest) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteKeypair)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
     if singleResult.Error != "" {
-        return errors.New(singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status != "" {
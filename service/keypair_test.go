@@ -17,6 +17,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -71,7 +72,7 @@ func TestKeypairService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -104,7 +105,7 @@ func TestKeypairService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -134,7 +135,7 @@ func TestKeypairService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -164,7 +165,7 @@ func TestKeypairService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -180,7 +181,7 @@ func TestKeypairService_Create(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.KeypairServicer.Create(testCase.Request.(*KeypairRequest))
+            _, err := s.KeypairServicer.Create(context.Background(), testCase.Request.(*KeypairRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -241,7 +242,7 @@ func TestKeypairService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -274,7 +275,7 @@ func TestKeypairService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -304,7 +305,7 @@ func TestKeypairService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -334,7 +335,7 @@ func TestKeypairService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -350,7 +351,7 @@ func TestKeypairService_Describe(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.KeypairServicer.Describe(testCase.Request.(*KeypairRequest))
+            _, err := s.KeypairServicer.Describe(context.Background(), testCase.Request.(*KeypairRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -401,7 +402,7 @@ func TestKeypairService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -434,7 +435,7 @@ func TestKeypairService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -464,7 +465,7 @@ func TestKeypairService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -494,7 +495,7 @@ func TestKeypairService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteKeypair).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -510,7 +511,7 @@ func TestKeypairService_Delete(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.KeypairServicer.Delete(testCase.Request.(*KeypairRequest))
+            err := s.KeypairServicer.Delete(context.Background(), testCase.Request.(*KeypairRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
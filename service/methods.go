@@ -6,6 +6,11 @@ const (
     MethodTerminateInstance      = "TERMINATE_INSTANCE"
     MethodDescribeInstance       = "DESCRIBE_INSTANCE"
     MethodTerminationProtection  = "MANAGE_TERMINATION_PROTECTION"
+    MethodStartInstance          = "START_INSTANCE"
+    MethodStopInstance           = "STOP_INSTANCE"
+    MethodRebootInstance         = "REBOOT_INSTANCE"
+    MethodResizeInstance         = "RESIZE_INSTANCE"
+    MethodManageExpiration       = "MANAGE_INSTANCE_EXPIRATION"
     MethodGetPlacementParameters = "ADDITIONAL_PARAM_ACTION"
 
     //images
@@ -18,6 +23,8 @@ const (
     MethodCreateAndAttachVolume = "CREATE_AND_ATTACH_VOLUME"
     MethodDeleteVolume          = "REMOVE_VOLUME"
     MethodDescribeVolume        = "DESCRIBE_VOLUME"
+    MethodDetachVolume          = "DETACH_VOLUME"
+    MethodResizeVolume          = "RESIZE_VOLUME"
 
     //scripts
     MethodCreateScript   = "UPLOAD_SCRIPT"
@@ -41,3 +48,15 @@ const (
     //chef
     MethodGetChefProfiles = "GET_DEFAULT_REGION_CHEF_PROFILES"
 )
+
+// NonIdempotentMethods contains methods which create new entities on every call,
+// so their requests must not be repeated if they could be processed by server
+var NonIdempotentMethods = map[string]bool{
+    MethodRunInstance:           true,
+    MethodCreateImage:           true,
+    MethodCreateVolume:          true,
+    MethodCreateAndAttachVolume: true,
+    MethodCreateScript:          true,
+    MethodCreateSchedule:        true,
+    MethodCreateKeypair:         true,
+}
@@ -5,6 +5,7 @@
 package smock
 
 import (
+	context "context"
 	reflect "reflect"
 	service "terraform-provider-m3/service"
 
@@ -35,62 +36,105 @@ func (m *MockVolumeServicer) EXPECT() *MockVolumeServicerMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockVolumeServicer) Create(arg0 *service.VolumeCreateRequest) (*service.Volume, error) {
+func (m *MockVolumeServicer) Create(arg0 context.Context, arg1 *service.VolumeCreateRequest) (*service.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*service.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockVolumeServicerMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeServicerMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVolumeServicer)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVolumeServicer)(nil).Create), arg0, arg1)
 }
 
 // CreateAndAttach mocks base method.
-func (m *MockVolumeServicer) CreateAndAttach(arg0 *service.VolumeCreateAndAttachRequest) (*service.Volume, error) {
+func (m *MockVolumeServicer) CreateAndAttach(arg0 context.Context, arg1 *service.VolumeCreateAndAttachRequest) (*service.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAndAttach", arg0)
+	ret := m.ctrl.Call(m, "CreateAndAttach", arg0, arg1)
 	ret0, _ := ret[0].(*service.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAndAttach indicates an expected call of CreateAndAttach.
-func (mr *MockVolumeServicerMockRecorder) CreateAndAttach(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeServicerMockRecorder) CreateAndAttach(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAndAttach", reflect.TypeOf((*MockVolumeServicer)(nil).CreateAndAttach), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAndAttach", reflect.TypeOf((*MockVolumeServicer)(nil).CreateAndAttach), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockVolumeServicer) Delete(arg0 *service.VolumeDeleteRequest) error {
+func (m *MockVolumeServicer) Delete(arg0 context.Context, arg1 *service.VolumeDeleteRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockVolumeServicerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeServicerMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVolumeServicer)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVolumeServicer)(nil).Delete), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockVolumeServicer) Describe(arg0 *service.VolumeDescribeRequest) (*service.Volume, error) {
+func (m *MockVolumeServicer) Describe(arg0 context.Context, arg1 *service.VolumeDescribeRequest) (*service.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockVolumeServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockVolumeServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockVolumeServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockVolumeServicer)(nil).Describe), arg0, arg1)
+}
+
+// Detach mocks base method.
+func (m *MockVolumeServicer) Detach(arg0 context.Context, arg1 *service.VolumeDetachRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detach", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockVolumeServicerMockRecorder) Detach(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockVolumeServicer)(nil).Detach), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockVolumeServicer) List(arg0 context.Context, arg1 *service.VolumeDescribeRequest) ([]service.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]service.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockVolumeServicerMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVolumeServicer)(nil).List), arg0, arg1)
+}
+
+// Resize mocks base method.
+func (m *MockVolumeServicer) Resize(arg0 context.Context, arg1 *service.VolumeResizeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resize", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resize indicates an expected call of Resize.
+func (mr *MockVolumeServicerMockRecorder) Resize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockVolumeServicer)(nil).Resize), arg0, arg1)
 }
 
 // MockScriptServicer is a mock of ScriptServicer interface.
@@ -117,47 +161,47 @@ func (m *MockScriptServicer) EXPECT() *MockScriptServicerMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockScriptServicer) Create(arg0 *service.ScriptCreateRequest) (*service.Script, error) {
+func (m *MockScriptServicer) Create(arg0 context.Context, arg1 *service.ScriptCreateRequest) (*service.Script, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*service.Script)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockScriptServicerMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockScriptServicerMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptServicer)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptServicer)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockScriptServicer) Delete(arg0 *service.ScriptDeleteRequest) error {
+func (m *MockScriptServicer) Delete(arg0 context.Context, arg1 *service.ScriptDeleteRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockScriptServicerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockScriptServicerMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptServicer)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptServicer)(nil).Delete), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockScriptServicer) Describe(arg0 *service.ScriptDescribeRequest) (*service.Script, error) {
+func (m *MockScriptServicer) Describe(arg0 context.Context, arg1 *service.ScriptDescribeRequest) (*service.Script, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Script)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockScriptServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockScriptServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockScriptServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockScriptServicer)(nil).Describe), arg0, arg1)
 }
 
 // MockScheduleServicer is a mock of ScheduleServicer interface.
@@ -184,47 +228,47 @@ func (m *MockScheduleServicer) EXPECT() *MockScheduleServicerMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockScheduleServicer) Create(arg0 *service.RequestSchedule) (*service.Schedule, error) {
+func (m *MockScheduleServicer) Create(arg0 context.Context, arg1 *service.RequestSchedule) (*service.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*service.Schedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockScheduleServicerMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockScheduleServicerMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduleServicer)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduleServicer)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockScheduleServicer) Delete(arg0 *service.RequestSchedule) error {
+func (m *MockScheduleServicer) Delete(arg0 context.Context, arg1 *service.RequestSchedule) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockScheduleServicerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockScheduleServicerMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduleServicer)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduleServicer)(nil).Delete), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockScheduleServicer) Describe(arg0 *service.RequestSchedule) (*service.Schedule, error) {
+func (m *MockScheduleServicer) Describe(arg0 context.Context, arg1 *service.RequestSchedule) (*service.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Schedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockScheduleServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockScheduleServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockScheduleServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockScheduleServicer)(nil).Describe), arg0, arg1)
 }
 
 // MockKeypairServicer is a mock of KeypairServicer interface.
@@ -251,47 +295,47 @@ func (m *MockKeypairServicer) EXPECT() *MockKeypairServicerMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockKeypairServicer) Create(arg0 *service.KeypairRequest) (*service.Keypair, error) {
+func (m *MockKeypairServicer) Create(arg0 context.Context, arg1 *service.KeypairRequest) (*service.Keypair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*service.Keypair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockKeypairServicerMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockKeypairServicerMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeypairServicer)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKeypairServicer)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockKeypairServicer) Delete(arg0 *service.KeypairRequest) error {
+func (m *MockKeypairServicer) Delete(arg0 context.Context, arg1 *service.KeypairRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockKeypairServicerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockKeypairServicerMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockKeypairServicer)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockKeypairServicer)(nil).Delete), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockKeypairServicer) Describe(arg0 *service.KeypairRequest) (*service.Keypair, error) {
+func (m *MockKeypairServicer) Describe(arg0 context.Context, arg1 *service.KeypairRequest) (*service.Keypair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Keypair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockKeypairServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockKeypairServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockKeypairServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockKeypairServicer)(nil).Describe), arg0, arg1)
 }
 
 // MockInstanceServicer is a mock of InstanceServicer interface.
@@ -318,89 +362,174 @@ func (m *MockInstanceServicer) EXPECT() *MockInstanceServicerMockRecorder {
 }
 
 // DeleteTags mocks base method.
-func (m *MockInstanceServicer) DeleteTags(arg0 *service.InstanceDeleteTagsRequest) error {
+func (m *MockInstanceServicer) DeleteTags(arg0 context.Context, arg1 *service.InstanceDeleteTagsRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTags", arg0)
+	ret := m.ctrl.Call(m, "DeleteTags", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTags indicates an expected call of DeleteTags.
-func (mr *MockInstanceServicerMockRecorder) DeleteTags(arg0 interface{}) *gomock.Call {
+func (mr *MockInstanceServicerMockRecorder) DeleteTags(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTags", reflect.TypeOf((*MockInstanceServicer)(nil).DeleteTags), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTags", reflect.TypeOf((*MockInstanceServicer)(nil).DeleteTags), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockInstanceServicer) Describe(arg0 *service.InstanceDescribeRequest) (*service.Instance, error) {
+func (m *MockInstanceServicer) Describe(arg0 context.Context, arg1 *service.InstanceDescribeRequest) (*service.Instance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Instance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockInstanceServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockInstanceServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockInstanceServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockInstanceServicer)(nil).Describe), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockInstanceServicer) List(arg0 context.Context, arg1 *service.InstanceDescribeRequest) ([]*service.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*service.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockInstanceServicerMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInstanceServicer)(nil).List), arg0, arg1)
+}
+
+// ManageExpiration mocks base method.
+func (m *MockInstanceServicer) ManageExpiration(arg0 context.Context, arg1 *service.InstanceExpirationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManageExpiration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ManageExpiration indicates an expected call of ManageExpiration.
+func (mr *MockInstanceServicerMockRecorder) ManageExpiration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManageExpiration", reflect.TypeOf((*MockInstanceServicer)(nil).ManageExpiration), arg0, arg1)
+}
+
+// ManageTerminationProtection mocks base method.
+func (m *MockInstanceServicer) ManageTerminationProtection(arg0 context.Context, arg1 *service.InstanceTerminationProtectionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManageTerminationProtection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ManageTerminationProtection indicates an expected call of ManageTerminationProtection.
+func (mr *MockInstanceServicerMockRecorder) ManageTerminationProtection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManageTerminationProtection", reflect.TypeOf((*MockInstanceServicer)(nil).ManageTerminationProtection), arg0, arg1)
+}
+
+// Reboot mocks base method.
+func (m *MockInstanceServicer) Reboot(arg0 context.Context, arg1 *service.InstancePowerRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reboot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reboot indicates an expected call of Reboot.
+func (mr *MockInstanceServicerMockRecorder) Reboot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reboot", reflect.TypeOf((*MockInstanceServicer)(nil).Reboot), arg0, arg1)
+}
+
+// Resize mocks base method.
+func (m *MockInstanceServicer) Resize(arg0 context.Context, arg1 *service.InstanceResizeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resize", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resize indicates an expected call of Resize.
+func (mr *MockInstanceServicerMockRecorder) Resize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockInstanceServicer)(nil).Resize), arg0, arg1)
 }
 
 // Run mocks base method.
-func (m *MockInstanceServicer) Run(arg0 *service.InstanceRunRequest) (*service.Instance, error) {
+func (m *MockInstanceServicer) Run(arg0 context.Context, arg1 *service.InstanceRunRequest) ([]*service.Instance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Run", arg0)
-	ret0, _ := ret[0].(*service.Instance)
+	ret := m.ctrl.Call(m, "Run", arg0, arg1)
+	ret0, _ := ret[0].([]*service.Instance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Run indicates an expected call of Run.
-func (mr *MockInstanceServicerMockRecorder) Run(arg0 interface{}) *gomock.Call {
+func (mr *MockInstanceServicerMockRecorder) Run(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockInstanceServicer)(nil).Run), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockInstanceServicer)(nil).Run), arg0, arg1)
 }
 
-// Terminate mocks base method.
-func (m *MockInstanceServicer) Terminate(arg0 *service.InstanceTerminateRequest) error {
+// Start mocks base method.
+func (m *MockInstanceServicer) Start(arg0 context.Context, arg1 *service.InstancePowerRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Terminate", arg0)
+	ret := m.ctrl.Call(m, "Start", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Terminate indicates an expected call of Terminate.
-func (mr *MockInstanceServicerMockRecorder) Terminate(arg0 interface{}) *gomock.Call {
+// Start indicates an expected call of Start.
+func (mr *MockInstanceServicerMockRecorder) Start(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terminate", reflect.TypeOf((*MockInstanceServicer)(nil).Terminate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockInstanceServicer)(nil).Start), arg0, arg1)
 }
 
-// UnlockTermination mocks base method.
-func (m *MockInstanceServicer) UnlockTermination(arg0 *service.InstanceTerminateRequest) error {
+// Stop mocks base method.
+func (m *MockInstanceServicer) Stop(arg0 context.Context, arg1 *service.InstancePowerRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnlockTermination", arg0)
+	ret := m.ctrl.Call(m, "Stop", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UnlockTermination indicates an expected call of UnlockTermination.
-func (mr *MockInstanceServicerMockRecorder) UnlockTermination(arg0 interface{}) *gomock.Call {
+// Stop indicates an expected call of Stop.
+func (mr *MockInstanceServicerMockRecorder) Stop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockInstanceServicer)(nil).Stop), arg0, arg1)
+}
+
+// Terminate mocks base method.
+func (m *MockInstanceServicer) Terminate(arg0 context.Context, arg1 *service.InstanceTerminateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Terminate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Terminate indicates an expected call of Terminate.
+func (mr *MockInstanceServicerMockRecorder) Terminate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockTermination", reflect.TypeOf((*MockInstanceServicer)(nil).UnlockTermination), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terminate", reflect.TypeOf((*MockInstanceServicer)(nil).Terminate), arg0, arg1)
 }
 
 // UpdateTags mocks base method.
-func (m *MockInstanceServicer) UpdateTags(arg0 *service.InstanceUpdateTagsRequest) error {
+func (m *MockInstanceServicer) UpdateTags(arg0 context.Context, arg1 *service.InstanceUpdateTagsRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTags", arg0)
+	ret := m.ctrl.Call(m, "UpdateTags", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateTags indicates an expected call of UpdateTags.
-func (mr *MockInstanceServicerMockRecorder) UpdateTags(arg0 interface{}) *gomock.Call {
+func (mr *MockInstanceServicerMockRecorder) UpdateTags(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockInstanceServicer)(nil).UpdateTags), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockInstanceServicer)(nil).UpdateTags), arg0, arg1)
 }
 
 // MockImageServicer is a mock of ImageServicer interface.
@@ -427,47 +556,47 @@ func (m *MockImageServicer) EXPECT() *MockImageServicerMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockImageServicer) Create(arg0 *service.ImageCreateRequest) (*service.Image, error) {
+func (m *MockImageServicer) Create(arg0 context.Context, arg1 *service.ImageCreateRequest) (*service.Image, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*service.Image)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockImageServicerMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockImageServicerMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImageServicer)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImageServicer)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockImageServicer) Delete(arg0 *service.DeleteImageRequest) error {
+func (m *MockImageServicer) Delete(arg0 context.Context, arg1 *service.DeleteImageRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockImageServicerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockImageServicerMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockImageServicer)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockImageServicer)(nil).Delete), arg0, arg1)
 }
 
 // Describe mocks base method.
-func (m *MockImageServicer) Describe(arg0 *service.ImageDescribeRequest) (*service.Image, error) {
+func (m *MockImageServicer) Describe(arg0 context.Context, arg1 *service.ImageDescribeRequest) (*service.Image, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Describe", arg0)
+	ret := m.ctrl.Call(m, "Describe", arg0, arg1)
 	ret0, _ := ret[0].(*service.Image)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Describe indicates an expected call of Describe.
-func (mr *MockImageServicerMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+func (mr *MockImageServicerMockRecorder) Describe(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockImageServicer)(nil).Describe), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockImageServicer)(nil).Describe), arg0, arg1)
 }
 
 // MockDataImageServicer is a mock of DataImageServicer interface.
@@ -494,18 +623,18 @@ func (m *MockDataImageServicer) EXPECT() *MockDataImageServicerMockRecorder {
 }
 
 // DataImageGetList mocks base method.
-func (m *MockDataImageServicer) DataImageGetList(arg0 *service.DefaultRequestParams) (*[]service.Image, error) {
+func (m *MockDataImageServicer) DataImageGetList(arg0 context.Context, arg1 *service.DefaultRequestParams) (*[]service.Image, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DataImageGetList", arg0)
+	ret := m.ctrl.Call(m, "DataImageGetList", arg0, arg1)
 	ret0, _ := ret[0].(*[]service.Image)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DataImageGetList indicates an expected call of DataImageGetList.
-func (mr *MockDataImageServicerMockRecorder) DataImageGetList(arg0 interface{}) *gomock.Call {
+func (mr *MockDataImageServicerMockRecorder) DataImageGetList(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataImageGetList", reflect.TypeOf((*MockDataImageServicer)(nil).DataImageGetList), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataImageGetList", reflect.TypeOf((*MockDataImageServicer)(nil).DataImageGetList), arg0, arg1)
 }
 
 // MockDataPlacementServicer is a mock of DataPlacementServicer interface.
@@ -532,18 +661,18 @@ func (m *MockDataPlacementServicer) EXPECT() *MockDataPlacementServicerMockRecor
 }
 
 // DataPlacementGetList mocks base method.
-func (m *MockDataPlacementServicer) DataPlacementGetList(request *service.PlacementParamsRequest) (*[]service.DataItem, error) {
+func (m *MockDataPlacementServicer) DataPlacementGetList(ctx context.Context, request *service.PlacementParamsRequest) (*[]service.DataItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DataPlacementGetList", request)
+	ret := m.ctrl.Call(m, "DataPlacementGetList", ctx, request)
 	ret0, _ := ret[0].(*[]service.DataItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DataPlacementGetList indicates an expected call of DataPlacementGetList.
-func (mr *MockDataPlacementServicerMockRecorder) DataPlacementGetList(request interface{}) *gomock.Call {
+func (mr *MockDataPlacementServicerMockRecorder) DataPlacementGetList(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataPlacementGetList", reflect.TypeOf((*MockDataPlacementServicer)(nil).DataPlacementGetList), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataPlacementGetList", reflect.TypeOf((*MockDataPlacementServicer)(nil).DataPlacementGetList), ctx, request)
 }
 
 // MockDataChefServicer is a mock of DataChefServicer interface.
@@ -570,16 +699,16 @@ func (m *MockDataChefServicer) EXPECT() *MockDataChefServicerMockRecorder {
 }
 
 // DataChefGetList mocks base method.
-func (m *MockDataChefServicer) DataChefGetList(request *service.DefaultRequestParams) (*service.DataChef, error) {
+func (m *MockDataChefServicer) DataChefGetList(ctx context.Context, request *service.DefaultRequestParams) (*service.DataChef, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DataChefGetList", request)
+	ret := m.ctrl.Call(m, "DataChefGetList", ctx, request)
 	ret0, _ := ret[0].(*service.DataChef)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DataChefGetList indicates an expected call of DataChefGetList.
-func (mr *MockDataChefServicerMockRecorder) DataChefGetList(request interface{}) *gomock.Call {
+func (mr *MockDataChefServicerMockRecorder) DataChefGetList(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataChefGetList", reflect.TypeOf((*MockDataChefServicer)(nil).DataChefGetList), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataChefGetList", reflect.TypeOf((*MockDataChefServicer)(nil).DataChefGetList), ctx, request)
 }
@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -56,7 +56,7 @@ func NewScheduleService(t client.Transporter) *ScheduleService {
     return &ScheduleService{trans: t}
 }
 
-func (s *ScheduleService) Create(request *RequestSchedule) (*Schedule, error) {
+func (s *ScheduleService) Create(ctx context.Context, request *RequestSchedule) (*Schedule, error) {
     req := struct {
         Schedule *RequestSchedule `json:"schedule"`
     }{request}
@@ -65,16 +65,16 @@ func (s *ScheduleService) Create(request *RequestSchedule) (*Schedule, error) {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     singleResult := r.Results[0]
     schedule := Schedule{}
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -90,21 +90,21 @@ func (s *ScheduleService) Create(request *RequestSchedule) (*Schedule, error) {
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *ScheduleService) Delete(request *RequestSchedule) error {
+func (s *ScheduleService) Delete(ctx context.Context, request *RequestSchedule) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteSchedule)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return errors.New(singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status == "SUCCESS" {
@@ -113,7 +113,7 @@ func (s *ScheduleService) Delete(request *RequestSchedule) error {
     return errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *ScheduleService) Describe(request *RequestSchedule) (*Schedule, error) {
+func (s *ScheduleService) Describe(ctx context.Context, request *RequestSchedule) (*Schedule, error) {
     req := struct {
         *DefaultRequestParams
         Cloud string `json:"cloud"`
@@ -126,16 +126,16 @@ func (s *ScheduleService) Describe(request *RequestSchedule) (*Schedule, error)
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     singleResult := r.Results[0]
     schedules := make([]Schedule, 0, 2)
 
     if singleResult.Error != "" {
-        return nil, errors.New(singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -148,7 +148,7 @@ func (s *ScheduleService) Describe(request *RequestSchedule) (*Schedule, error)
                 return &schedule, nil
             }
         }
-        return nil, errors.New("404")
+        return nil, notFound("schedule %s not found", request.Name)
     }
 
     return nil, errors.New("neither 'result' nor 'error' in response")
@@ -1,6 +1,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -65,7 +66,7 @@ func TestScheduleService_Create(t *testing.T) {
                     Schedule *RequestSchedule `json:"schedule"`
                 }{request.(*RequestSchedule)}
                 m.EXPECT().MakePayload(req, MethodCreateSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -104,7 +105,7 @@ func TestScheduleService_Create(t *testing.T) {
                     Schedule *RequestSchedule `json:"schedule"`
                 }{request.(*RequestSchedule)}
                 m.EXPECT().MakePayload(req, MethodCreateSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -137,7 +138,7 @@ func TestScheduleService_Create(t *testing.T) {
                     Schedule *RequestSchedule `json:"schedule"`
                 }{request.(*RequestSchedule)}
                 m.EXPECT().MakePayload(req, MethodCreateSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -170,7 +171,7 @@ func TestScheduleService_Create(t *testing.T) {
                     Schedule *RequestSchedule `json:"schedule"`
                 }{request.(*RequestSchedule)}
                 m.EXPECT().MakePayload(req, MethodCreateSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -186,7 +187,7 @@ func TestScheduleService_Create(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.ScheduleServicer.Create(testCase.Request.(*RequestSchedule))
+            _, err := s.ScheduleServicer.Create(context.Background(), testCase.Request.(*RequestSchedule))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -263,7 +264,7 @@ func TestScheduleService_Describe(t *testing.T) {
                     Cloud:                request.(*RequestSchedule).Cloud,
                 }
                 m.EXPECT().MakePayload(req, MethodDescribeSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -324,7 +325,7 @@ func TestScheduleService_Describe(t *testing.T) {
                     Cloud:                request.(*RequestSchedule).Cloud,
                 }
                 m.EXPECT().MakePayload(req, MethodDescribeSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -361,7 +362,7 @@ func TestScheduleService_Describe(t *testing.T) {
                     Cloud:                request.(*RequestSchedule).Cloud,
                 }
                 m.EXPECT().MakePayload(req, MethodDescribeSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -405,7 +406,7 @@ func TestScheduleService_Describe(t *testing.T) {
                     Cloud:                request.(*RequestSchedule).Cloud,
                 }
                 m.EXPECT().MakePayload(req, MethodDescribeSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -421,7 +422,7 @@ func TestScheduleService_Describe(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.ScheduleServicer.Describe(testCase.Request.(*RequestSchedule))
+            _, err := s.ScheduleServicer.Describe(context.Background(), testCase.Request.(*RequestSchedule))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -473,7 +474,7 @@ func TestScheduleService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -506,7 +507,7 @@ func TestScheduleService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -536,7 +537,7 @@ func TestScheduleService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -566,7 +567,7 @@ func TestScheduleService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteSchedule).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -582,7 +583,7 @@ func TestScheduleService_Delete(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.ScheduleServicer.Delete(testCase.Request.(*RequestSchedule))
+            err := s.ScheduleServicer.Delete(context.Background(), testCase.Request.(*RequestSchedule))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -1,9 +1,9 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
-    "fmt"
     "terraform-provider-m3/client"
 )
 
@@ -50,22 +50,22 @@ func NewScriptService(t client.Transporter) *ScriptService {
     return &ScriptService{trans: t}
 }
 
-func (s *ScriptService) Create(request *ScriptCreateRequest) (*Script, error) {
+func (s *ScriptService) Create(ctx context.Context, request *ScriptCreateRequest) (*Script, error) {
     payload, err := s.trans.MakePayload(request, MethodCreateScript)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     script := new(Script)
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -80,21 +80,21 @@ func (s *ScriptService) Create(request *ScriptCreateRequest) (*Script, error) {
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *ScriptService) Delete(request *ScriptDeleteRequest) error {
+func (s *ScriptService) Delete(ctx context.Context, request *ScriptDeleteRequest) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteScript)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return errors.New(singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status == "SUCCESS" {
@@ -104,15 +104,15 @@ func (s *ScriptService) Delete(request *ScriptDeleteRequest) error {
     return errors.New("neither 'result' nor 'error' in response")
 }
 
-func (s *ScriptService) Describe(request *ScriptDescribeRequest) (*Script, error) {
+func (s *ScriptService) Describe(ctx context.Context, request *ScriptDescribeRequest) (*Script, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeScript)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     scripts := make([]Script, 0, 4)
@@ -120,7 +120,7 @@ func (s *ScriptService) Describe(request *ScriptDescribeRequest) (*Script, error
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, errors.New(singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -134,7 +134,7 @@ func (s *ScriptService) Describe(request *ScriptDescribeRequest) (*Script, error
                 return &script, err
             }
         }
-        return nil, errors.New("404")
+        return nil, notFound("script %s not found", request.FileName)
         // success
     }
 
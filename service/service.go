@@ -1,6 +1,10 @@
 package service
 
-import "terraform-provider-m3/client"
+import (
+    "context"
+    "errors"
+    "terraform-provider-m3/client"
+)
 
 //go:generate go install github.com/golang/mock/mockgen@v1.6.0
 //go:generate mockgen -source ./service.go -destination ./mock/service_mock.go -package smock
@@ -13,63 +17,72 @@ type DefaultRequestParams struct {
 
 // VolumeServicer interface that provides methods to work with volumes
 type VolumeServicer interface {
-    Create(*VolumeCreateRequest) (*Volume, error)
-    CreateAndAttach(*VolumeCreateAndAttachRequest) (*Volume, error)
-    Delete(*VolumeDeleteRequest) error
-    Describe(*VolumeDescribeRequest) (*Volume, error)
+    Create(context.Context, *VolumeCreateRequest) (*Volume, error)
+    CreateAndAttach(context.Context, *VolumeCreateAndAttachRequest) (*Volume, error)
+    Delete(context.Context, *VolumeDeleteRequest) error
+    Detach(context.Context, *VolumeDetachRequest) error
+    Describe(context.Context, *VolumeDescribeRequest) (*Volume, error)
+    List(context.Context, *VolumeDescribeRequest) ([]Volume, error)
+    Resize(context.Context, *VolumeResizeRequest) error
 }
 
 // ScriptServicer interface that provides methods to work with scripts
 type ScriptServicer interface {
-    Create(*ScriptCreateRequest) (*Script, error)
-    Delete(*ScriptDeleteRequest) error
-    Describe(*ScriptDescribeRequest) (*Script, error)
+    Create(context.Context, *ScriptCreateRequest) (*Script, error)
+    Delete(context.Context, *ScriptDeleteRequest) error
+    Describe(context.Context, *ScriptDescribeRequest) (*Script, error)
 }
 
 // ScheduleServicer interface that provides methods to work with schedules
 type ScheduleServicer interface {
-    Create(*RequestSchedule) (*Schedule, error)
-    Delete(*RequestSchedule) error
-    Describe(*RequestSchedule) (*Schedule, error)
+    Create(context.Context, *RequestSchedule) (*Schedule, error)
+    Delete(context.Context, *RequestSchedule) error
+    Describe(context.Context, *RequestSchedule) (*Schedule, error)
 }
 
 // KeypairServicer interface that provides methods to work with keypairs
 type KeypairServicer interface {
-    Create(*KeypairRequest) (*Keypair, error)
-    Delete(*KeypairRequest) error
-    Describe(*KeypairRequest) (*Keypair, error)
+    Create(context.Context, *KeypairRequest) (*Keypair, error)
+    Delete(context.Context, *KeypairRequest) error
+    Describe(context.Context, *KeypairRequest) (*Keypair, error)
 }
 
 // InstanceServicer interface that provides methods to work with instances
 type InstanceServicer interface {
-    Run(*InstanceRunRequest) (*Instance, error)
-    Terminate(*InstanceTerminateRequest) error
-    Describe(*InstanceDescribeRequest) (*Instance, error)
-    UnlockTermination(*InstanceTerminateRequest) error
-    UpdateTags(*InstanceUpdateTagsRequest) error
-    DeleteTags(*InstanceDeleteTagsRequest) error
+    Run(context.Context, *InstanceRunRequest) ([]*Instance, error)
+    Terminate(context.Context, *InstanceTerminateRequest) error
+    Describe(context.Context, *InstanceDescribeRequest) (*Instance, error)
+    List(context.Context, *InstanceDescribeRequest) ([]*Instance, error)
+    ManageTerminationProtection(context.Context, *InstanceTerminationProtectionRequest) error
+    Start(context.Context, *InstancePowerRequest) error
+    Stop(context.Context, *InstancePowerRequest) error
+    Reboot(context.Context, *InstancePowerRequest) error
+    Resize(context.Context, *InstanceResizeRequest) error
+    ManageExpiration(context.Context, *InstanceExpirationRequest) error
+    UpdateTags(context.Context, *InstanceUpdateTagsRequest) error
+    DeleteTags(context.Context, *InstanceDeleteTagsRequest) error
 }
 
 // ImageServicer interface that provides methods to work with images
 type ImageServicer interface {
-    Create(*ImageCreateRequest) (*Image, error)
-    Delete(*DeleteImageRequest) error
-    Describe(*ImageDescribeRequest) (*Image, error)
+    Create(context.Context, *ImageCreateRequest) (*Image, error)
+    Delete(context.Context, *DeleteImageRequest) error
+    Describe(context.Context, *ImageDescribeRequest) (*Image, error)
 }
 
 // DataImageServicer interface that provides methods to work with DataImages
 type DataImageServicer interface {
-    DataImageGetList(*DefaultRequestParams) (*[]Image, error)
+    DataImageGetList(context.Context, *DefaultRequestParams) (*[]Image, error)
 }
 
 // DataPlacementServicer interface that provides methods to work with DataPlacementParams
 type DataPlacementServicer interface {
-    DataPlacementGetList(request *PlacementParamsRequest) (*[]DataItem, error)
+    DataPlacementGetList(ctx context.Context, request *PlacementParamsRequest) (*[]DataItem, error)
 }
 
 // DataChefServicer interface that provides methods to work with ChefProfiles
 type DataChefServicer interface {
-    DataChefGetList(request *DefaultRequestParams) (*DataChef, error)
+    DataChefGetList(ctx context.Context, request *DefaultRequestParams) (*DataChef, error)
 }
 
 type Service struct {
@@ -97,3 +110,28 @@ func NewService(c *client.Client) *Service {
         DataChefServicer:      NewDataChefService(c.Transporter),
     }
 }
+
+// action sends request of method which returns no data, it succeeds when result of the action has status
+func action(ctx context.Context, trans client.Transporter, request interface{}, method string) error {
+    payload, err := trans.MakePayload(request, method)
+    if err != nil {
+        return err
+    }
+
+    r, err := trans.Do(ctx, payload)
+    if err != nil {
+        return transportError(err)
+    }
+
+    singleResult := r.Results[0]
+
+    if singleResult.Error != "" {
+        return resultError(singleResult)
+    }
+
+    if singleResult.Status != "" {
+        return nil
+    }
+
+    return errors.New("neither 'result' nor 'error' in response")
+}
@@ -1,25 +1,38 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "fmt"
-    "strings"
     "terraform-provider-m3/client"
 )
 
 // AvailableVolumeState is for volume in available state
 var AvailableVolumeState = "available"
 
+// CreatingVolumeState is for volume which is being created
+var CreatingVolumeState = "creating"
+
+// ErrorVolumeState is for volume which creation is failed
+var ErrorVolumeState = "error"
+
+// DetachingVolumeState is for volume which is being detached from instance
+var DetachingVolumeState = "detaching"
+
+// ModifyingVolumeState is for volume which is being resized
+var ModifyingVolumeState = "modifying"
+
 // Volume contains information about fields of volume
 type Volume struct {
-    TenantName string `json:"tenantName"`
-    Region     string `json:"regionName"`
-    Name       string `json:"volumeName"`
-    VolumeID   string `json:"volumeId"`
-    State      string `json:"state"`
-    System     bool   `json:"system"`
-    SizeLabel  int    `json:"sizeInGb"`
+    TenantName  string `json:"tenantName"`
+    Region      string `json:"regionName"`
+    Name        string `json:"volumeName"`
+    VolumeID    string `json:"volumeId"`
+    State       string `json:"state"`
+    StateReason string `json:"stateReason"`
+    System      bool   `json:"system"`
+    SizeLabel   int    `json:"sizeInGb"`
 }
 
 // VolumeCreateRequest request to create volume
@@ -43,6 +56,13 @@ type VolumeDeleteRequest struct {
     VolumeID string `json:"volumeId"`
 }
 
+// VolumeDetachRequest request to detach volume from instance
+type VolumeDetachRequest struct {
+    *DefaultRequestParams
+    VolumeID   string `json:"volumeId"`
+    InstanceId string `json:"instanceId"`
+}
+
 // VolumeDescribeRequest request to describe volume
 type VolumeDescribeRequest struct {
     *DefaultRequestParams
@@ -50,6 +70,13 @@ type VolumeDescribeRequest struct {
     InstanceId string   `json:"instanceId"`
 }
 
+// VolumeResizeRequest request to change size of volume, the size can only be increased
+type VolumeResizeRequest struct {
+    *DefaultRequestParams
+    VolumeID string `json:"volumeId"`
+    SizeInGB int    `json:"sizeInGB"`
+}
+
 // VolumeService contains fields needed to implement VolumeServicer interface
 type VolumeService struct {
     trans client.Transporter
@@ -60,15 +87,15 @@ func NewVolumeService(t client.Transporter) *VolumeService {
 }
 
 // Create is method to create volume
-func (s *VolumeService) Create(request *VolumeCreateRequest) (*Volume, error) {
+func (s *VolumeService) Create(ctx context.Context, request *VolumeCreateRequest) (*Volume, error) {
     payload, err := s.trans.MakePayload(request, MethodCreateVolume)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     volumes := make([]Volume, 0, 2)
@@ -76,7 +103,7 @@ func (s *VolumeService) Create(request *VolumeCreateRequest) (*Volume, error) {
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -96,15 +123,15 @@ func (s *VolumeService) Create(request *VolumeCreateRequest) (*Volume, error) {
 }
 
 // CreateAndAttach is method to create and attach volume
-func (s *VolumeService) CreateAndAttach(request *VolumeCreateAndAttachRequest) (*Volume, error) {
+func (s *VolumeService) CreateAndAttach(ctx context.Context, request *VolumeCreateAndAttachRequest) (*Volume, error) {
     payload, err := s.trans.MakePayload(request, MethodCreateAndAttachVolume)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     volumes := make([]Volume, 0, 2)
@@ -112,7 +139,7 @@ func (s *VolumeService) CreateAndAttach(request *VolumeCreateAndAttachRequest) (
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
 
     if singleResult.Data != "" {
@@ -131,24 +158,21 @@ func (s *VolumeService) CreateAndAttach(request *VolumeCreateAndAttachRequest) (
 }
 
 // Delete method to delete volume
-func (s *VolumeService) Delete(request *VolumeDeleteRequest) error {
+func (s *VolumeService) Delete(ctx context.Context, request *VolumeDeleteRequest) error {
     payload, err := s.trans.MakePayload(request, MethodDeleteVolume)
     if err != nil {
         return err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return err
+        return transportError(err)
     }
 
     singleResult := r.Results[0]
 
     if singleResult.Error != "" {
-        if strings.Contains(singleResult.Error, "No unique volume found by volume ID") {
-            return errors.New("404")
-        }
-        return fmt.Errorf("%+v", singleResult.Error)
+        return resultError(singleResult)
     }
 
     if singleResult.Status == "SUCCESS" {
@@ -157,16 +181,21 @@ func (s *VolumeService) Delete(request *VolumeDeleteRequest) error {
     return errors.New("neither 'result' nor 'error' in response")
 }
 
+// Detach method to detach volume from instance, the volume becomes available after that
+func (s *VolumeService) Detach(ctx context.Context, request *VolumeDetachRequest) error {
+    return action(ctx, s.trans, request, MethodDetachVolume)
+}
+
 // Describe describes volume
-func (s *VolumeService) Describe(request *VolumeDescribeRequest) (*Volume, error) {
+func (s *VolumeService) Describe(ctx context.Context, request *VolumeDescribeRequest) (*Volume, error) {
     payload, err := s.trans.MakePayload(request, MethodDescribeVolume)
     if err != nil {
         return nil, err
     }
 
-    r, err := s.trans.Do(payload)
+    r, err := s.trans.Do(ctx, payload)
     if err != nil {
-        return nil, err
+        return nil, transportError(err)
     }
 
     volumes := make([]Volume, 0, 2)
@@ -183,15 +212,45 @@ func (s *VolumeService) Describe(request *VolumeDescribeRequest) (*Volume, error
                 return &volume, err
             }
         }
-        return nil, errors.New("404")
+        return nil, notFound("volume %s not found", request.VolumeIds[0])
         // success
     }
 
     if singleResult.Error != "" {
-        if strings.Contains(singleResult.Error, "No unique volume found by volume ID") {
-            return nil, errors.New("404")
-        }
-        return nil, fmt.Errorf("%+v", singleResult.Error)
+        return nil, resultError(singleResult)
     }
     return nil, errors.New("neither 'result' nor 'error' in response")
 }
+
+// List describes all volumes matching request, volumes attached to instance are described if InstanceId is set
+func (s *VolumeService) List(ctx context.Context, request *VolumeDescribeRequest) ([]Volume, error) {
+    payload, err := s.trans.MakePayload(request, MethodDescribeVolume)
+    if err != nil {
+        return nil, err
+    }
+
+    r, err := s.trans.Do(ctx, payload)
+    if err != nil {
+        return nil, transportError(err)
+    }
+
+    singleResult := r.Results[0]
+
+    if singleResult.Error != "" {
+        return nil, resultError(singleResult)
+    }
+
+    volumes := make([]Volume, 0, 2)
+    if singleResult.Data != "" {
+        err = json.Unmarshal([]byte(singleResult.Data), &volumes)
+        if err != nil {
+            return nil, err
+        }
+    }
+    return volumes, nil
+}
+
+// Resize is method to change size of volume
+func (s *VolumeService) Resize(ctx context.Context, request *VolumeResizeRequest) error {
+    return action(ctx, s.trans, request, MethodResizeVolume)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+    "context"
     "encoding/json"
     "errors"
     "github.com/golang/mock/gomock"
@@ -57,7 +58,7 @@ func TestVolumeService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -90,7 +91,7 @@ func TestVolumeService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -120,7 +121,7 @@ func TestVolumeService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -150,7 +151,7 @@ func TestVolumeService_Create(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -166,7 +167,7 @@ func TestVolumeService_Create(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.VolumeServicer.Create(testCase.Request.(*VolumeCreateRequest))
+            _, err := s.VolumeServicer.Create(context.Background(), testCase.Request.(*VolumeCreateRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -225,7 +226,7 @@ func TestVolumeService_CreateAndAttach(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateAndAttachVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -258,7 +259,7 @@ func TestVolumeService_CreateAndAttach(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateAndAttachVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -288,7 +289,7 @@ func TestVolumeService_CreateAndAttach(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateAndAttachVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -318,7 +319,7 @@ func TestVolumeService_CreateAndAttach(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodCreateAndAttachVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -334,7 +335,7 @@ func TestVolumeService_CreateAndAttach(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.VolumeServicer.CreateAndAttach(testCase.Request.(*VolumeCreateAndAttachRequest))
+            _, err := s.VolumeServicer.CreateAndAttach(context.Background(), testCase.Request.(*VolumeCreateAndAttachRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -393,7 +394,7 @@ func TestVolumeService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -426,7 +427,7 @@ func TestVolumeService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -456,7 +457,7 @@ func TestVolumeService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -486,7 +487,7 @@ func TestVolumeService_Delete(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDeleteVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -502,7 +503,7 @@ func TestVolumeService_Delete(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            err := s.VolumeServicer.Delete(testCase.Request.(*VolumeDeleteRequest))
+            err := s.VolumeServicer.Delete(context.Background(), testCase.Request.(*VolumeDeleteRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -569,7 +570,7 @@ func TestVolumeService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -618,7 +619,7 @@ func TestVolumeService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -651,7 +652,7 @@ func TestVolumeService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, errors.New("some error"))
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
             },
         },
 
@@ -681,7 +682,7 @@ func TestVolumeService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
 
@@ -712,7 +713,7 @@ func TestVolumeService_Describe(t *testing.T) {
 
             MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
                 m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
-                m.EXPECT().Do(nil).Return(DoResponse, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
             },
         },
     }
@@ -728,7 +729,7 @@ func TestVolumeService_Describe(t *testing.T) {
             c := &client.Client{Transporter: mockTransporter}
             s := NewService(c)
 
-            _, err := s.VolumeServicer.Describe(testCase.Request.(*VolumeDescribeRequest))
+            _, err := s.VolumeServicer.Describe(context.Background(), testCase.Request.(*VolumeDescribeRequest))
 
             if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
                 t.Fatal()
@@ -738,3 +739,302 @@ func TestVolumeService_Describe(t *testing.T) {
     }
 
 }
+
+func TestVolumeService_List(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        WantCount    int
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    response := func(data, err string) func() *client.M3BatchResult {
+        return func() *client.M3BatchResult {
+            raw := &client.M3RawResult{
+                ID:     "123456789",
+                Status: "SUCCESS",
+                Error:  err,
+                Data:   data,
+            }
+
+            return &client.M3BatchResult{
+                Results: []*client.M3RawResult{raw},
+            }
+        }
+    }
+    volumes, _ := json.Marshal([]Volume{
+        {Name: "root", VolumeID: "vol-1", System: true},
+        {Name: "data", VolumeID: "vol-2"},
+    })
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantCount: 2,
+
+            Request: &VolumeDescribeRequest{InstanceId: "i-1"},
+
+            DoResponse: response(string(volumes), ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "No volumes if data is empty",
+
+            Request: &VolumeDescribeRequest{InstanceId: "i-1"},
+
+            DoResponse: response("", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error in response not empty",
+
+            WantErr: true,
+
+            Request: &VolumeDescribeRequest{},
+
+            DoResponse: response("", "some error"),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error on during DO",
+
+            WantErr: true,
+
+            Request: &VolumeDescribeRequest{},
+
+            DoResponse: func() *client.M3BatchResult {
+                return nil
+            },
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDescribeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, errors.New("some error"))
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.Request, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            got, err := s.VolumeServicer.List(context.Background(), testCase.Request.(*VolumeDescribeRequest))
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal()
+            }
+            if len(got) != testCase.WantCount {
+                t.Fatalf("got %d volumes instead of %d", len(got), testCase.WantCount)
+            }
+        })
+    }
+}
+
+func TestVolumeService_Resize(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    response := func(status, err string) func() *client.M3BatchResult {
+        return func() *client.M3BatchResult {
+            raw := &client.M3RawResult{
+                ID:     "123456789",
+                Status: status,
+                Error:  err,
+                Data:   "",
+            }
+
+            return &client.M3BatchResult{
+                Results: []*client.M3RawResult{raw},
+            }
+        }
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantErr: false,
+
+            Request: &VolumeResizeRequest{VolumeID: "vol-1", SizeInGB: 20},
+
+            DoResponse: response("SUCCESS", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error in response not empty",
+
+            WantErr: true,
+
+            Request: &VolumeResizeRequest{VolumeID: "vol-1", SizeInGB: 5},
+
+            DoResponse: response("FAILED", "Size of volume can not be decreased"),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if neither result nor error in response",
+
+            WantErr: true,
+
+            Request: &VolumeResizeRequest{VolumeID: "vol-1", SizeInGB: 20},
+
+            DoResponse: response("", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodResizeVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.Request, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            err := s.VolumeServicer.Resize(context.Background(), testCase.Request.(*VolumeResizeRequest))
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal()
+            }
+        })
+    }
+}
+
+func TestVolumeService_Detach(t *testing.T) {
+    type MockBehavior func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult)
+    type TestCase struct {
+        Request      interface{}
+        Name         string
+        WantErr      bool
+        DoResponse   func() *client.M3BatchResult
+        MockBehavior MockBehavior
+    }
+
+    response := func(status, err string) func() *client.M3BatchResult {
+        return func() *client.M3BatchResult {
+            raw := &client.M3RawResult{
+                ID:     "123456789",
+                Status: status,
+                Error:  err,
+                Data:   "",
+            }
+
+            return &client.M3BatchResult{
+                Results: []*client.M3RawResult{raw},
+            }
+        }
+    }
+
+    testTable := []TestCase{
+        {
+            Name: "OK",
+
+            WantErr: false,
+
+            Request: &VolumeDetachRequest{VolumeID: "vol-1", InstanceId: "i-1"},
+
+            DoResponse: response("SUCCESS", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDetachVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if error in response not empty",
+
+            WantErr: true,
+
+            Request: &VolumeDetachRequest{VolumeID: "vol-1", InstanceId: "i-2"},
+
+            DoResponse: response("FAILED", "Volume vol-1 is not attached to instance i-2"),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDetachVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+
+        {
+            Name: "Got error if neither result nor error in response",
+
+            WantErr: true,
+
+            Request: &VolumeDetachRequest{VolumeID: "vol-1", InstanceId: "i-1"},
+
+            DoResponse: response("", ""),
+
+            MockBehavior: func(m *cmock.MockTransporter, request interface{}, DoResponse *client.M3BatchResult) {
+                m.EXPECT().MakePayload(request, MethodDetachVolume).Return(nil, nil)
+                m.EXPECT().Do(gomock.Any(), nil).Return(DoResponse, nil)
+            },
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            ctl := gomock.NewController(t)
+            defer ctl.Finish()
+
+            mockTransporter := cmock.NewMockTransporter(ctl)
+            testCase.MockBehavior(mockTransporter, testCase.Request, testCase.DoResponse())
+
+            c := &client.Client{Transporter: mockTransporter}
+            s := NewService(c)
+
+            err := s.VolumeServicer.Detach(context.Background(), testCase.Request.(*VolumeDetachRequest))
+
+            if (!testCase.WantErr && err != nil) || (testCase.WantErr && err == nil) {
+                t.Fatal()
+            }
+        })
+    }
+}
@@ -0,0 +1,56 @@
+package simulator
+
+import (
+    "encoding/json"
+    "terraform-provider-m3/service"
+)
+
+// DefaultImage is the name of image available in simulator unless Options.Images are set
+const DefaultImage = "CentOS7_64-bit"
+
+// DefaultChefRole is the role of chef profile returned unless Options.ChefProfiles are set
+const DefaultChefRole = "base"
+
+func defaultImages(cloud string) []service.Image {
+    return []service.Image{{
+        Alias:     DefaultImage,
+        Name:      DefaultImage,
+        ImageID:   "ami-centos7",
+        OsType:    "LINUX",
+        ImageType: "PUBLIC",
+        State:     service.AvailableImageState,
+        Cloud:     cloud,
+    }}
+}
+
+func defaultChefProfiles() *service.DataChef {
+    // roles are declared with anonymous struct type, so it is simpler to build profile from JSON
+    profile := &service.DataChef{}
+    _ = json.Unmarshal([]byte(`{
+        "serverId": "chef-server",
+        "roles": [
+            {"roleName": "`+DefaultChefRole+`", "minCpu": 1, "minMemoryMb": 512},
+            {"roleName": "database", "minCpu": 2, "minMemoryMb": 4096, "requiredParameters": ["db_password"]}
+        ],
+        "zones": ["default"],
+        "chefOrganization": "simulator"
+    }`), profile)
+    return profile
+}
+
+func defaultPlacementParams() []service.DataItem {
+    return []service.DataItem{{
+        Name: "clusterId",
+        Options: []service.DataOption{{
+            Value: "cluster-1",
+            Title: "Cluster 1",
+            Items: []service.DataItem{{
+                Name: "datastoreId",
+                Options: []service.DataOption{
+                    {Value: "datastore-1", Title: "Datastore 1"},
+                    {Value: "datastore-2", Title: "Datastore 2"},
+                },
+            }},
+        }},
+    }}
+}
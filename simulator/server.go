@@ -0,0 +1,296 @@
+// Package simulator implements in-memory Maestro3 API for offline testing of the provider.
+// It speaks the same protocol as Maestro3: requests and responses are encrypted with AES-GCM,
+// requests are signed with HMAC headers and actions are sent in JSON-RPC batches.
+package simulator
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "sync"
+    "terraform-provider-m3/client"
+    "terraform-provider-m3/service"
+    "time"
+)
+
+const (
+    headerAuthentication = "maestro-authentication"
+    headerDate           = "maestro-date"
+    headerAccessKey      = "maestro-accesskey"
+    headerUserIdentifier = "maestro-user-identifier"
+    headerAsync          = "maestro-sdk-async"
+
+    resultsPath = "/results"
+
+    statusSuccess = "SUCCESS"
+    statusFailed  = "FAILED"
+)
+
+// Options configures simulated Maestro3 API
+type Options struct {
+    AccessKey      string
+    SecretKey      string
+    UserIdentifier string
+    // Cloud is reported for every resource created in simulator, AWS by default
+    Cloud string
+    // TransitionDelay is the time resources spend in transitional states like starting or terminating
+    TransitionDelay time.Duration
+    // KeepVolumesOnTerminate detaches data volumes of terminated instances instead of deleting them with instances
+    KeepVolumesOnTerminate bool
+    // Images are available for launching instances, a CentOS image is added by default
+    Images []service.Image
+    // ChefProfiles are returned for every region, a default profile is added by default
+    ChefProfiles *service.DataChef
+    // PlacementParams are returned for every region, vSphere cluster tree is added by default
+    PlacementParams []service.DataItem
+}
+
+// Server is running simulated Maestro3 API
+type Server struct {
+    *httptest.Server
+
+    opts  Options
+    mu    sync.Mutex
+    state *state
+    // asyncResults contains results of requests sent in async mode by request id
+    asyncResults map[string]*asyncResult
+}
+
+type asyncResult struct {
+    result  *client.M3RawResult
+    readyAt time.Time
+}
+
+// New starts simulated Maestro3 API, it must be closed after usage
+func New(opts Options) *Server {
+    if opts.Cloud == "" {
+        opts.Cloud = "AWS"
+    }
+    if opts.Images == nil {
+        opts.Images = defaultImages(opts.Cloud)
+    }
+    if opts.ChefProfiles == nil {
+        opts.ChefProfiles = defaultChefProfiles()
+    }
+    if opts.PlacementParams == nil {
+        opts.PlacementParams = defaultPlacementParams()
+    }
+
+    s := &Server{
+        opts:         opts,
+        state:        newState(opts),
+        asyncResults: make(map[string]*asyncResult),
+    }
+    s.Server = httptest.NewServer(s)
+    return s
+}
+
+// VolumeTags returns tags propagated to volume from instance, they are not available through API
+func (s *Server) VolumeTags(volumeID string) map[string]string {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    v, ok := s.state.volumes[volumeID]
+    if !ok {
+        return nil
+    }
+    tags := make(map[string]string, len(v.tags))
+    for key, value := range v.tags {
+        tags[key] = value
+    }
+    return tags
+}
+
+// UserData returns decoded user data and startup script of instance, they are not available through API
+func (s *Server) UserData(instanceID string) (userData, startupScript string) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i, ok := s.state.instances[instanceID]
+    if !ok {
+        return "", ""
+    }
+    return i.userData, i.startupScript
+}
+
+// Credentials returns credentials accepted by simulator
+func (s *Server) Credentials() *client.Credentials {
+    return &client.Credentials{
+        URL:            s.URL,
+        AccessKey:      s.opts.AccessKey,
+        SecretKey:      s.opts.SecretKey,
+        UserIdentifier: s.opts.UserIdentifier,
+    }
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    if err := s.authenticate(r.Header); err != nil {
+        http.Error(w, err.Error(), http.StatusUnauthorized)
+        return
+    }
+
+    body, err := io.ReadAll(r.Body)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    decrypted, err := s.decrypt(body)
+    if err != nil {
+        http.Error(w, "can not decrypt request: "+err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    var results []*client.M3RawResult
+    if strings.HasSuffix(r.URL.Path, resultsPath) {
+        results, err = s.pollResults(decrypted)
+    } else {
+        results, err = s.execute(decrypted, r.Header.Get(headerAsync) == "true")
+    }
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    response, err := json.Marshal(&client.M3BatchResult{Results: results})
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    encrypted, err := s.encrypt(response)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    _, _ = w.Write([]byte(encrypted))
+}
+
+// execute runs every action of the batch
+func (s *Server) execute(body []byte, async bool) ([]*client.M3RawResult, error) {
+    payloads := make([]*client.DefaultPayload, 0, 1)
+    if err := json.Unmarshal(body, &payloads); err != nil {
+        return nil, fmt.Errorf("invalid batch: %v", err)
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    results := make([]*client.M3RawResult, 0, len(payloads))
+    for _, payload := range payloads {
+        if payload == nil || payload.Params == nil {
+            return nil, errors.New("invalid action")
+        }
+        result := s.state.handle(payload, now)
+        if async {
+            s.asyncResults[payload.ID] = &asyncResult{result: result, readyAt: now.Add(s.opts.TransitionDelay)}
+            result = &client.M3RawResult{ID: payload.ID, Status: client.StatusInProgress}
+        }
+        results = append(results, result)
+    }
+    return results, nil
+}
+
+// pollResults returns results of async requests which are ready
+func (s *Server) pollResults(body []byte) ([]*client.M3RawResult, error) {
+    request := new(client.AsyncResultsRequest)
+    if err := json.Unmarshal(body, request); err != nil {
+        return nil, fmt.Errorf("invalid results request: %v", err)
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    results := make([]*client.M3RawResult, 0, len(request.RequestIds))
+    for _, id := range request.RequestIds {
+        stored, ok := s.asyncResults[id]
+        switch {
+        case !ok:
+            results = append(results, failedResult(id, &apiError{StatusCode: http.StatusNotFound, Message: "request " + id + " not found"}))
+        case now.Before(stored.readyAt):
+            results = append(results, &client.M3RawResult{ID: id, Status: client.StatusInProgress})
+        default:
+            delete(s.asyncResults, id)
+            results = append(results, stored.result)
+        }
+    }
+    return results, nil
+}
+
+func (s *Server) authenticate(header http.Header) error {
+    if header.Get(headerAccessKey) != s.opts.AccessKey {
+        return errors.New("unknown access key")
+    }
+    if header.Get(headerUserIdentifier) != s.opts.UserIdentifier {
+        return errors.New("unknown user identifier")
+    }
+    date := header.Get(headerDate)
+    if _, err := strconv.ParseInt(date, 10, 64); err != nil {
+        return errors.New("invalid date")
+    }
+    if !hmac.Equal([]byte(header.Get(headerAuthentication)), []byte(s.sign(date))) {
+        return errors.New("invalid signature")
+    }
+    return nil
+}
+
+func (s *Server) sign(date string) string {
+    mac := hmac.New(sha256.New, []byte(s.opts.SecretKey+date))
+    mac.Write([]byte("M3-POST:" + s.opts.AccessKey + ":" + date + ":" + s.opts.UserIdentifier))
+
+    var builder strings.Builder
+    for _, element := range mac.Sum(nil) {
+        builder.WriteString(strconv.FormatInt((int64(element)&0xff)+0x100, 16))
+    }
+    return builder.String()
+}
+
+func (s *Server) gcm() (cipher.AEAD, error) {
+    block, err := aes.NewCipher([]byte(s.opts.SecretKey))
+    if err != nil {
+        return nil, err
+    }
+    return cipher.NewGCM(block)
+}
+
+func (s *Server) decrypt(body []byte) ([]byte, error) {
+    data, err := base64.StdEncoding.DecodeString(string(body))
+    if err != nil {
+        return nil, err
+    }
+    aesgcm, err := s.gcm()
+    if err != nil {
+        return nil, err
+    }
+    if len(data) < aesgcm.NonceSize() {
+        return nil, errors.New("ciphertext too short")
+    }
+    return aesgcm.Open(nil, data[:aesgcm.NonceSize()], data[aesgcm.NonceSize():], nil)
+}
+
+func (s *Server) encrypt(data []byte) (string, error) {
+    aesgcm, err := s.gcm()
+    if err != nil {
+        return "", err
+    }
+    iv := make([]byte, aesgcm.NonceSize())
+    if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(append(iv, aesgcm.Seal(nil, iv, data, nil)...)), nil
+}
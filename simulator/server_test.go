@@ -0,0 +1,298 @@
+package simulator
+
+import (
+    "context"
+    "crypto/tls"
+    "errors"
+    "terraform-provider-m3/client"
+    "terraform-provider-m3/service"
+    "testing"
+    "time"
+)
+
+const (
+    testAccessKey      = "simulator-access-key"
+    testSecretKey      = "0123456789abcdef0123456789abcdef"
+    testUserIdentifier = "user@example.com"
+)
+
+func newTestService(server *Server, async bool) *service.Service {
+    conf := client.NewConfig(server.Credentials(), &tls.Config{})
+    conf.Async = client.AsyncConfig{Enabled: async, PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second}
+    return service.NewService(client.NewClient(conf))
+}
+
+func TestServer_Instance(t *testing.T) {
+    type TestCase struct {
+        Name  string
+        Async bool
+    }
+
+    testTable := []TestCase{
+        {
+            Name:  "OK instance lifecycle in sync mode",
+            Async: false,
+        },
+
+        {
+            Name:  "OK instance lifecycle in async mode",
+            Async: true,
+        },
+    }
+
+    for _, testCase := range testTable {
+        t.Run(testCase.Name, func(t *testing.T) {
+            server := New(Options{
+                AccessKey:       testAccessKey,
+                SecretKey:       testSecretKey,
+                UserIdentifier:  testUserIdentifier,
+                TransitionDelay: 50 * time.Millisecond,
+            })
+            defer server.Close()
+            s := newTestService(server, testCase.Async)
+            ctx := context.Background()
+            params := &service.DefaultRequestParams{Region: "EU_WEST", TenantName: "TEST"}
+
+            instances, err := s.InstanceServicer.Run(ctx, &service.InstanceRunRequest{
+                DefaultRequestParams: params,
+                InstanceName:         "simulated",
+                Image:                DefaultImage,
+                Shape:                "SMALL",
+                InstancesCount:       2,
+                Tags:                 map[string]interface{}{"env": "test"},
+            })
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(instances) != 2 || instances[0].InstanceID == instances[1].InstanceID {
+                t.Fatalf("got unexpected instances %+v", instances)
+            }
+            instance := instances[0]
+
+            describe := &service.InstanceDescribeRequest{DefaultRequestParams: params, InstanceIds: []string{instance.InstanceID}}
+            time.Sleep(60 * time.Millisecond)
+            described, err := s.InstanceServicer.Describe(ctx, describe)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if described.State != service.InstanceStates.Running || len(described.Tags) != 1 {
+                t.Fatalf("got unexpected instance %+v", described)
+            }
+
+            power := &service.InstancePowerRequest{DefaultRequestParams: params, InstanceID: instance.InstanceID}
+            if err = s.InstanceServicer.Start(ctx, power); !errors.Is(err, service.ErrConflict) {
+                t.Fatalf("got error '%v' instead of conflict on start of running instance", err)
+            }
+            // instance must be stopped for resize in AWS
+            resize := func(ctx context.Context, request *service.InstancePowerRequest) error {
+                return s.InstanceServicer.Resize(ctx, &service.InstanceResizeRequest{
+                    DefaultRequestParams: params,
+                    InstanceID:           request.InstanceID,
+                    Shape:                "LARGE",
+                })
+            }
+            if err = resize(ctx, power); !errors.Is(err, service.ErrConflict) {
+                t.Fatalf("got error '%v' instead of conflict on resize of running instance", err)
+            }
+            for _, step := range []struct {
+                action func(context.Context, *service.InstancePowerRequest) error
+                state  string
+            }{
+                {action: s.InstanceServicer.Stop, state: service.InstanceStates.Stopped},
+                {action: resize, state: service.InstanceStates.Stopped},
+                {action: s.InstanceServicer.Start, state: service.InstanceStates.Running},
+                {action: s.InstanceServicer.Reboot, state: service.InstanceStates.Running},
+            } {
+                if err = step.action(ctx, power); err != nil {
+                    t.Fatal(err)
+                }
+                time.Sleep(60 * time.Millisecond)
+                described, err = s.InstanceServicer.Describe(ctx, describe)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if described.State != step.state {
+                    t.Fatalf("got state '%s' instead of '%s'", described.State, step.state)
+                }
+            }
+            if described.Shape != "LARGE" {
+                t.Fatalf("got shape '%s' instead of resized one", described.Shape)
+            }
+
+            err = s.InstanceServicer.Terminate(ctx, &service.InstanceTerminateRequest{DefaultRequestParams: params, InstanceID: instance.InstanceID})
+            if err != nil {
+                t.Fatal(err)
+            }
+            time.Sleep(60 * time.Millisecond)
+            if _, err = s.InstanceServicer.Describe(ctx, describe); err == nil || !errors.Is(err, service.ErrNotFound) {
+                t.Fatalf("got error '%v' instead of not found", err)
+            }
+        })
+    }
+}
+
+func TestServer_Resources(t *testing.T) {
+    server := New(Options{AccessKey: testAccessKey, SecretKey: testSecretKey, UserIdentifier: testUserIdentifier})
+    defer server.Close()
+    s := newTestService(server, false)
+    ctx := context.Background()
+    params := &service.DefaultRequestParams{Region: "EU_WEST", TenantName: "TEST"}
+
+    keypair, err := s.KeypairServicer.Create(ctx, &service.KeypairRequest{
+        KeypairTenantName: &service.KeypairTenantName{TenantName: "TEST"},
+        KeypairContent:    &service.KeypairContent{Content: "ssh-rsa AAAA"},
+        Name:              "key",
+        Email:             testUserIdentifier,
+    })
+    if err != nil || keypair.Name != "key" {
+        t.Fatalf("keypair is not created: %v", err)
+    }
+    if _, err = s.KeypairServicer.Describe(ctx, &service.KeypairRequest{Name: "key"}); err != nil {
+        t.Fatal(err)
+    }
+
+    volume, err := s.VolumeServicer.Create(ctx, &service.VolumeCreateRequest{DefaultRequestParams: params, VolumeName: "data", SizeInGB: 10})
+    if err != nil {
+        t.Fatal(err)
+    }
+    described, err := s.VolumeServicer.Describe(ctx, &service.VolumeDescribeRequest{DefaultRequestParams: params, VolumeIds: []string{volume.VolumeID}})
+    if err != nil || described.State != service.AvailableVolumeState {
+        t.Fatalf("volume is not available: %+v %v", described, err)
+    }
+    resize := &service.VolumeResizeRequest{DefaultRequestParams: params, VolumeID: volume.VolumeID, SizeInGB: 20}
+    if err = s.VolumeServicer.Resize(ctx, resize); err != nil {
+        t.Fatal(err)
+    }
+    resize.SizeInGB = 5
+    if err = s.VolumeServicer.Resize(ctx, resize); err == nil {
+        t.Fatal("size of volume is decreased")
+    }
+    listed, err := s.VolumeServicer.List(ctx, &service.VolumeDescribeRequest{DefaultRequestParams: params, VolumeIds: []string{volume.VolumeID}})
+    if err != nil || len(listed) != 1 || listed[0].SizeLabel != 20 {
+        t.Fatalf("volume is not resized: %+v %v", listed, err)
+    }
+    if err = s.VolumeServicer.Delete(ctx, &service.VolumeDeleteRequest{DefaultRequestParams: params, VolumeID: volume.VolumeID}); err != nil {
+        t.Fatal(err)
+    }
+    if err = s.VolumeServicer.Delete(ctx, &service.VolumeDeleteRequest{DefaultRequestParams: params, VolumeID: volume.VolumeID}); err == nil || !errors.Is(err, service.ErrNotFound) {
+        t.Fatalf("got error '%v' instead of not found", err)
+    }
+
+    chef, err := s.DataChefServicer.DataChefGetList(ctx, params)
+    if err != nil || len(chef.Roles) == 0 || chef.Roles[0].RoleName != DefaultChefRole {
+        t.Fatalf("chef profiles are not returned: %+v %v", chef, err)
+    }
+
+    // image can be created only from existing instance
+    if _, err = s.ImageServicer.Create(ctx, &service.ImageCreateRequest{DefaultRequestParams: params, InstanceID: "i-missing", ImageName: "image"}); err == nil {
+        t.Fatal("image of unknown instance is created")
+    }
+}
+
+func TestServer_Volumes(t *testing.T) {
+    server := New(Options{
+        AccessKey:              testAccessKey,
+        SecretKey:              testSecretKey,
+        UserIdentifier:         testUserIdentifier,
+        TransitionDelay:        50 * time.Millisecond,
+        KeepVolumesOnTerminate: true,
+    })
+    defer server.Close()
+    s := newTestService(server, false)
+    ctx := context.Background()
+    params := &service.DefaultRequestParams{Region: "EU_WEST", TenantName: "TEST"}
+
+    instances, err := s.InstanceServicer.Run(ctx, &service.InstanceRunRequest{
+        DefaultRequestParams: params,
+        InstanceName:         "simulated",
+        Image:                DefaultImage,
+        Shape:                "SMALL",
+        InstancesCount:       1,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    instanceID := instances[0].InstanceID
+    state := func(volumeID string) string {
+        described, err := s.VolumeServicer.Describe(ctx, &service.VolumeDescribeRequest{DefaultRequestParams: params, VolumeIds: []string{volumeID}})
+        if err != nil {
+            t.Fatal(err)
+        }
+        return described.State
+    }
+
+    data, err := s.VolumeServicer.CreateAndAttach(ctx, &service.VolumeCreateAndAttachRequest{
+        DefaultRequestParams: params,
+        VolumeName:           "data",
+        SizeInGB:             10,
+        InstanceId:           instanceID,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    logs, err := s.VolumeServicer.CreateAndAttach(ctx, &service.VolumeCreateAndAttachRequest{
+        DefaultRequestParams: params,
+        VolumeName:           "logs",
+        SizeInGB:             10,
+        InstanceId:           instanceID,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    time.Sleep(60 * time.Millisecond)
+
+    // attached volume must be detached before removal
+    remove := &service.VolumeDeleteRequest{DefaultRequestParams: params, VolumeID: data.VolumeID}
+    if err = s.VolumeServicer.Delete(ctx, remove); err == nil {
+        t.Fatal("attached volume is removed")
+    }
+    if err = s.VolumeServicer.Resize(ctx, &service.VolumeResizeRequest{DefaultRequestParams: params, VolumeID: data.VolumeID, SizeInGB: 20}); err != nil {
+        t.Fatal(err)
+    }
+    if got := state(data.VolumeID); got != service.ModifyingVolumeState {
+        t.Fatalf("got state '%s' of resized volume instead of '%s'", got, service.ModifyingVolumeState)
+    }
+    time.Sleep(60 * time.Millisecond)
+    if got := state(data.VolumeID); got != service.InUseState {
+        t.Fatalf("got state '%s' of resized volume instead of '%s'", got, service.InUseState)
+    }
+    if err = s.VolumeServicer.Detach(ctx, &service.VolumeDetachRequest{DefaultRequestParams: params, VolumeID: data.VolumeID, InstanceId: instanceID}); err != nil {
+        t.Fatal(err)
+    }
+    time.Sleep(60 * time.Millisecond)
+    if got := state(data.VolumeID); got != service.AvailableVolumeState {
+        t.Fatalf("got state '%s' of detached volume instead of '%s'", got, service.AvailableVolumeState)
+    }
+    if err = s.VolumeServicer.Delete(ctx, remove); err != nil {
+        t.Fatal(err)
+    }
+
+    // data volumes of terminated instance are kept, only system volume is deleted with it
+    err = s.InstanceServicer.Terminate(ctx, &service.InstanceTerminateRequest{DefaultRequestParams: params, InstanceID: instanceID})
+    if err != nil {
+        t.Fatal(err)
+    }
+    time.Sleep(60 * time.Millisecond)
+    volumes, err := s.VolumeServicer.List(ctx, &service.VolumeDescribeRequest{DefaultRequestParams: params})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(volumes) != 1 || volumes[0].VolumeID != logs.VolumeID || volumes[0].State != service.AvailableVolumeState {
+        t.Fatalf("got volumes %+v instead of available logs volume", volumes)
+    }
+}
+
+func TestServer_Authentication(t *testing.T) {
+    server := New(Options{AccessKey: testAccessKey, SecretKey: testSecretKey, UserIdentifier: testUserIdentifier})
+    defer server.Close()
+
+    creds := server.Credentials()
+    creds.SecretKey = "fedcba9876543210fedcba9876543210"
+    conf := client.NewConfig(creds, &tls.Config{})
+    s := service.NewService(client.NewClient(conf))
+
+    _, err := s.DataImageServicer.DataImageGetList(context.Background(), &service.DefaultRequestParams{Region: "EU_WEST", TenantName: "TEST"})
+    if !errors.Is(err, service.ErrPermissionDenied) {
+        t.Fatalf("got error '%v' instead of permission denied", err)
+    }
+}
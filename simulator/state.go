@@ -0,0 +1,924 @@
+package simulator
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+    "strings"
+    "terraform-provider-m3/client"
+    "terraform-provider-m3/service"
+    "time"
+)
+
+// maxUserDataSize limits decoded user data of instance in bytes, as AWS does
+const maxUserDataSize = 16 * 1024
+
+// apiError is returned by handlers as failed result with status code
+type apiError struct {
+    StatusCode int
+    Message    string
+}
+
+func (e *apiError) Error() string {
+    return e.Message
+}
+
+func notFound(format string, args ...interface{}) *apiError {
+    return &apiError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+    return &apiError{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func failedResult(id string, err *apiError) *client.M3RawResult {
+    return &client.M3RawResult{ID: id, Status: statusFailed, Error: err.Message, StatusCode: err.StatusCode}
+}
+
+// handler executes action with JSON encoded request body and returns data of result,
+// nil data means result without data, only with success status
+type handler func(s *state, body []byte, now time.Time) (interface{}, *apiError)
+
+var handlers = map[string]handler{
+    service.MethodRunInstance:            (*state).runInstance,
+    service.MethodTerminateInstance:      (*state).terminateInstance,
+    service.MethodDescribeInstance:       (*state).describeInstances,
+    service.MethodTerminationProtection:  (*state).manageTerminationProtection,
+    service.MethodStartInstance:          (*state).startInstance,
+    service.MethodStopInstance:           (*state).stopInstance,
+    service.MethodRebootInstance:         (*state).rebootInstance,
+    service.MethodResizeInstance:         (*state).resizeInstance,
+    service.MethodManageExpiration:       (*state).manageExpiration,
+    service.MethodGetPlacementParameters: (*state).placementParams,
+    service.MethodUpdateTags:             (*state).updateTags,
+    service.MethodDeleteTags:             (*state).deleteTags,
+
+    service.MethodCreateImage:   (*state).createImage,
+    service.MethodDeleteImage:   (*state).deleteImage,
+    service.MethodDescribeImage: (*state).describeImages,
+
+    service.MethodCreateVolume:          (*state).createVolume,
+    service.MethodCreateAndAttachVolume: (*state).createAndAttachVolume,
+    service.MethodDeleteVolume:          (*state).deleteVolume,
+    service.MethodDescribeVolume:        (*state).describeVolumes,
+    service.MethodDetachVolume:          (*state).detachVolume,
+    service.MethodResizeVolume:          (*state).resizeVolume,
+
+    service.MethodCreateScript:   (*state).createScript,
+    service.MethodDeleteScript:   (*state).deleteScript,
+    service.MethodDescribeScript: (*state).describeScripts,
+
+    service.MethodCreateSchedule:   (*state).createSchedule,
+    service.MethodDeleteSchedule:   (*state).deleteSchedule,
+    service.MethodDescribeSchedule: (*state).describeSchedules,
+
+    service.MethodCreateKeypair:   (*state).createKeypair,
+    service.MethodDeleteKeypair:   (*state).deleteKeypair,
+    service.MethodDescribeKeypair: (*state).describeKeypairs,
+
+    service.MethodGetChefProfiles: (*state).chefProfiles,
+}
+
+type instance struct {
+    service.Instance
+    // transitionAt is the time when instance leaves starting, stopping or terminating state
+    transitionAt time.Time
+    // userData and startupScript are passed to instance at boot, Maestro3 does not return them in instance description
+    userData      string
+    startupScript string
+}
+
+type image struct {
+    service.Image
+    availableAt time.Time
+}
+
+type volume struct {
+    service.Volume
+    instanceID  string
+    availableAt time.Time
+    // tags are propagated from instance, Maestro3 does not return them in volume description
+    tags map[string]string
+}
+
+// state contains entities of simulated Maestro3, it is guarded by Server mutex
+type state struct {
+    opts    Options
+    counter int
+
+    instances map[string]*instance
+    images    map[string]*image
+    volumes   map[string]*volume
+    scripts   map[string]*service.Script
+    schedules map[string]*service.Schedule
+    keypairs  map[string]*service.Keypair
+}
+
+func newState(opts Options) *state {
+    s := &state{
+        opts:      opts,
+        instances: make(map[string]*instance),
+        images:    make(map[string]*image),
+        volumes:   make(map[string]*volume),
+        scripts:   make(map[string]*service.Script),
+        schedules: make(map[string]*service.Schedule),
+        keypairs:  make(map[string]*service.Keypair),
+    }
+    for _, seeded := range opts.Images {
+        s.images[seeded.ImageID] = &image{Image: seeded}
+    }
+    return s
+}
+
+// handle executes single action of the batch
+func (s *state) handle(payload *client.DefaultPayload, now time.Time) *client.M3RawResult {
+    h, ok := handlers[payload.Type]
+    if !ok {
+        return failedResult(payload.ID, badRequest("unknown action '%s'", payload.Type))
+    }
+
+    s.advance(now)
+    data, apiErr := h(s, []byte(payload.Params.Body), now)
+    if apiErr != nil {
+        return failedResult(payload.ID, apiErr)
+    }
+
+    result := &client.M3RawResult{ID: payload.ID, Status: statusSuccess, StatusCode: http.StatusOK}
+    if data != nil {
+        encoded, err := json.Marshal(data)
+        if err != nil {
+            return failedResult(payload.ID, &apiError{StatusCode: http.StatusInternalServerError, Message: err.Error()})
+        }
+        result.Data = string(encoded)
+    }
+    return result
+}
+
+// advance moves entities from transitional states once their time has come
+func (s *state) advance(now time.Time) {
+    for id, i := range s.instances {
+        if now.Before(i.transitionAt) {
+            continue
+        }
+        switch i.State {
+        case service.InstanceStates.Starting:
+            i.State = service.InstanceStates.Running
+        case service.InstanceStates.Stopping:
+            i.State = service.InstanceStates.Stopped
+        case service.InstanceStates.Terminating:
+            delete(s.instances, id)
+            for _, v := range s.volumes {
+                if v.instanceID != id {
+                    continue
+                }
+                if s.opts.KeepVolumesOnTerminate && !v.System {
+                    v.instanceID = ""
+                    v.State = service.AvailableVolumeState
+                    continue
+                }
+                delete(s.volumes, v.VolumeID)
+            }
+        }
+    }
+    for _, i := range s.images {
+        if i.State != service.AvailableImageState && !now.Before(i.availableAt) {
+            i.State = service.AvailableImageState
+        }
+    }
+    for _, v := range s.volumes {
+        if v.State != service.AvailableVolumeState && v.State != service.InUseState && !now.Before(v.availableAt) {
+            v.State = service.AvailableVolumeState
+            if v.instanceID != "" {
+                v.State = service.InUseState
+            }
+        }
+    }
+}
+
+func (s *state) nextID(prefix string) string {
+    s.counter++
+    return fmt.Sprintf("%s-%08x", prefix, s.counter)
+}
+
+func decode(body []byte, request interface{}) *apiError {
+    if err := json.Unmarshal(body, request); err != nil {
+        return badRequest("invalid request: %v", err)
+    }
+    return nil
+}
+
+func (s *state) findImage(idOrName string) *image {
+    if i, ok := s.images[idOrName]; ok {
+        return i
+    }
+    for _, i := range s.images {
+        if i.Name == idOrName {
+            return i
+        }
+    }
+    return nil
+}
+
+func (s *state) runInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceRunRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.DefaultRequestParams == nil || request.Region == "" || request.TenantName == "" {
+        return nil, badRequest("region and tenantName are required")
+    }
+    img := s.findImage(request.Image)
+    if img == nil {
+        return nil, notFound("image '%s' is not found", request.Image)
+    }
+    if request.KeyName != "" {
+        if _, ok := s.keypairs[request.KeyName]; !ok {
+            return nil, notFound("key '%s' is not found", request.KeyName)
+        }
+    }
+    userData, err := base64.StdEncoding.DecodeString(request.UserData)
+    if err != nil {
+        return nil, badRequest("userData is not base64 encoded: %v", err)
+    }
+    if len(userData) > maxUserDataSize {
+        return nil, badRequest("userData exceeds %d bytes", maxUserDataSize)
+    }
+    if request.StartupScript != "" {
+        if _, ok := s.scripts[request.StartupScript]; !ok {
+            return nil, notFound("script '%s' is not found", request.StartupScript)
+        }
+    }
+    count := request.InstancesCount
+    if count < 1 {
+        count = 1
+    }
+
+    var stopAt, terminateAt string
+    if request.StopAfter != nil && request.StopAfter.StopAfter > 0 {
+        stopAt = now.Add(time.Duration(request.StopAfter.StopAfter) * time.Hour).UTC().Format(time.RFC3339)
+    }
+    if request.TerminateAfter != nil && request.TerminateAfter.TerminateAfter > 0 {
+        terminateAt = now.Add(time.Duration(request.TerminateAfter.TerminateAfter) * time.Hour).UTC().Format(time.RFC3339)
+    }
+
+    tags := make([]service.Tag, 0, len(request.Tags))
+    for key, value := range request.Tags {
+        tags = append(tags, service.Tag{Key: key, Value: fmt.Sprint(value)})
+    }
+    sortTags(tags)
+
+    result := service.InstancesResultData{Instances: make([]service.Instance, 0, count)}
+    for n := 0; n < count; n++ {
+        i := &instance{
+            Instance: service.Instance{
+                InstanceID:        s.nextID("i"),
+                Cloud:             s.opts.Cloud,
+                InstanceName:      request.InstanceName,
+                TenantName:        request.TenantName,
+                Region:            request.Region,
+                State:             service.InstanceStates.Starting,
+                Created:           now.UTC().Format(time.RFC3339),
+                Architecture:      "x86_64",
+                Image:             img.ImageID,
+                Shape:             request.Shape,
+                PrivateIP:         fmt.Sprintf("10.0.%d.%d", s.counter/256, s.counter%256),
+                LockedTermination: request.LockedTermination,
+                ChefEnabled:       request.ChefEnabled,
+                InstanceChefUUID:  request.InstanceChefUUID,
+                ChefProfile:       request.ChefProfile,
+                KeyName:           request.KeyName,
+                Owner:             request.Owner,
+                AvailabilityZone:  request.Region + "a",
+                StopAt:            stopAt,
+                TerminateAt:       terminateAt,
+                AdditionalData:    request.AdditionalData,
+                Tags:              tags,
+            },
+            transitionAt:  now.Add(s.opts.TransitionDelay),
+            userData:      string(userData),
+            startupScript: request.StartupScript,
+        }
+        root := &volume{
+            Volume: service.Volume{
+                TenantName: request.TenantName,
+                Region:     request.Region,
+                Name:       i.InstanceID + "-root",
+                VolumeID:   s.nextID("vol"),
+                State:      service.InUseState,
+                System:     true,
+                SizeLabel:  8,
+            },
+            instanceID: i.InstanceID,
+        }
+        s.volumes[root.VolumeID] = root
+        i.VolumesIds = []string{root.VolumeID}
+        s.instances[i.InstanceID] = i
+        result.Instances = append(result.Instances, i.Instance)
+    }
+    return result, nil
+}
+
+func (s *state) terminateInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceTerminateRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    if i.LockedTermination {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: "termination of instance " + i.InstanceID + " is locked"}
+    }
+    if i.State != service.InstanceStates.Terminating {
+        i.State = service.InstanceStates.Terminating
+        i.transitionAt = now.Add(s.opts.TransitionDelay)
+    }
+    return nil, nil
+}
+
+// power moves instance from state to transitional state, which is left after transition delay
+func (s *state) power(body []byte, now time.Time, from, to string) (interface{}, *apiError) {
+    request := &service.InstancePowerRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    if i.State != from {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: fmt.Sprintf("instance %s is in '%s' state, expected '%s'", i.InstanceID, i.State, from)}
+    }
+    i.State = to
+    i.transitionAt = now.Add(s.opts.TransitionDelay)
+    return nil, nil
+}
+
+func (s *state) startInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    return s.power(body, now, service.InstanceStates.Stopped, service.InstanceStates.Starting)
+}
+
+func (s *state) stopInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    return s.power(body, now, service.InstanceStates.Running, service.InstanceStates.Stopping)
+}
+
+func (s *state) rebootInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    return s.power(body, now, service.InstanceStates.Running, service.InstanceStates.Starting)
+}
+
+// resizeInstance changes shape of instance, running instance is rebooted with the new shape
+func (s *state) resizeInstance(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceResizeRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    if request.Shape == "" {
+        return nil, badRequest("shape is required")
+    }
+    if !service.IsResizeSupported(i.Cloud) {
+        return nil, badRequest("resize is not supported for cloud %s", i.Cloud)
+    }
+    switch {
+    case i.State == service.InstanceStates.Stopped:
+        i.Shape = request.Shape
+    case i.State == service.InstanceStates.Running && !service.IsStopRequiredForResize(i.Cloud):
+        i.Shape = request.Shape
+        i.State = service.InstanceStates.Starting
+        i.transitionAt = now.Add(s.opts.TransitionDelay)
+    default:
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: fmt.Sprintf("instance %s can not be resized in '%s' state", i.InstanceID, i.State)}
+    }
+    return nil, nil
+}
+
+// manageExpiration sets or clears times of delayed stop and terminate of instance, they are not performed by simulator
+func (s *state) manageExpiration(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceExpirationRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    for _, at := range []string{request.StopAt, request.TerminateAt} {
+        if at == "" {
+            continue
+        }
+        parsed, err := time.Parse(time.RFC3339, at)
+        if err != nil {
+            return nil, badRequest("invalid time '%s', RFC3339 is expected", at)
+        }
+        if parsed.Before(now) {
+            return nil, badRequest("time '%s' is in the past", at)
+        }
+    }
+    i.StopAt = request.StopAt
+    i.TerminateAt = request.TerminateAt
+    return nil, nil
+}
+
+func (s *state) describeInstances(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceDescribeRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    result := service.InstancesResultData{Instances: make([]service.Instance, 0, len(request.InstanceIds))}
+    for _, id := range request.InstanceIds {
+        if i, ok := s.instances[id]; ok {
+            result.Instances = append(result.Instances, i.Instance)
+        }
+    }
+    if len(request.InstanceIds) == 0 {
+        for _, i := range s.instances {
+            result.Instances = append(result.Instances, i.Instance)
+        }
+    }
+    return result, nil
+}
+
+func (s *state) manageTerminationProtection(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceTerminationProtectionRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    switch request.Action {
+    case service.TerminationProtectionActions.Enable:
+        if !service.IsTerminationProtectionSupported(i.Cloud) {
+            return nil, badRequest("termination protection is not supported for cloud %s", i.Cloud)
+        }
+        i.LockedTermination = true
+    case service.TerminationProtectionActions.Disable:
+        i.LockedTermination = false
+    default:
+        return nil, badRequest("unknown action '%s'", request.Action)
+    }
+    return i.Instance, nil
+}
+
+func (s *state) updateTags(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceUpdateTagsRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.Id]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.Id)
+    }
+
+    tags := make(map[string]string, len(i.Tags)+len(request.Tags))
+    if !request.Overwrite {
+        for _, tag := range i.Tags {
+            tags[tag.Key] = tag.Value
+        }
+    }
+    for key, value := range request.Tags {
+        tags[key] = fmt.Sprint(value)
+    }
+    i.Tags = make([]service.Tag, 0, len(tags))
+    for key, value := range tags {
+        i.Tags = append(i.Tags, service.Tag{Key: key, Value: value})
+    }
+    sortTags(i.Tags)
+
+    for _, v := range s.instanceVolumes(i.InstanceID, request.VolumeIds) {
+        if request.Overwrite || v.tags == nil {
+            v.tags = make(map[string]string, len(request.Tags))
+        }
+        for key, value := range request.Tags {
+            v.tags[key] = fmt.Sprint(value)
+        }
+    }
+    return i.Tags, nil
+}
+
+func (s *state) deleteTags(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.InstanceDeleteTagsRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.Id]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.Id)
+    }
+
+    // tags are accepted both as keys and as key=value pairs
+    removed := make(map[string]bool, len(request.Tags))
+    for _, tag := range request.Tags {
+        removed[strings.SplitN(tag, "=", 2)[0]] = true
+    }
+    tags := make([]service.Tag, 0, len(i.Tags))
+    for _, tag := range i.Tags {
+        if !removed[tag.Key] {
+            tags = append(tags, tag)
+        }
+    }
+    i.Tags = tags
+
+    for _, v := range s.instanceVolumes(i.InstanceID, request.VolumeIds) {
+        for key := range removed {
+            delete(v.tags, key)
+        }
+    }
+    return i.Tags, nil
+}
+
+// instanceVolumes returns volumes attached to instance by their IDs
+func (s *state) instanceVolumes(instanceID string, ids []string) []*volume {
+    volumes := make([]*volume, 0, len(ids))
+    for _, id := range ids {
+        if v, ok := s.volumes[id]; ok && v.instanceID == instanceID {
+            volumes = append(volumes, v)
+        }
+    }
+    return volumes
+}
+
+func (s *state) placementParams(body []byte, now time.Time) (interface{}, *apiError) {
+    return s.opts.PlacementParams, nil
+}
+
+func (s *state) createImage(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.ImageCreateRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    i, ok := s.instances[request.InstanceID]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceID)
+    }
+    if s.findImage(request.ImageName) != nil {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: "image with name '" + request.ImageName + "' already exists"}
+    }
+
+    created := &image{
+        Image: service.Image{
+            TenantName:  i.TenantName,
+            Region:      i.Region,
+            Alias:       request.ImageName,
+            Name:        request.ImageName,
+            Description: request.Description,
+            CreatedDate: int(now.Unix()),
+            ImageID:     s.nextID("ami"),
+            OsType:      "LINUX",
+            ImageType:   "PRIVATE",
+            State:       service.PendingImageState,
+            Cloud:       i.Cloud,
+            Owner:       request.Owner,
+        },
+        availableAt: now.Add(s.opts.TransitionDelay),
+    }
+    s.images[created.ImageID] = created
+    return []service.Image{created.Image}, nil
+}
+
+func (s *state) deleteImage(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.DeleteImageRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if _, ok := s.images[request.ImageID]; !ok {
+        return nil, notFound("no unique image found by image ID %s", request.ImageID)
+    }
+    delete(s.images, request.ImageID)
+    return nil, nil
+}
+
+func (s *state) describeImages(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.ImageDescribeRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    images := make([]service.Image, 0, len(s.images))
+    for _, i := range s.images {
+        if len(request.ImageIds) == 0 || contains(request.ImageIds, i.ImageID) {
+            images = append(images, i.Image)
+        }
+    }
+    sort.Slice(images, func(a, b int) bool { return images[a].ImageID < images[b].ImageID })
+    return images, nil
+}
+
+func (s *state) addVolume(tenant, region, name string, size int, instanceID string, now time.Time) *volume {
+    created := &volume{
+        Volume: service.Volume{
+            TenantName: tenant,
+            Region:     region,
+            Name:       name,
+            VolumeID:   s.nextID("vol"),
+            State:      service.CreatingVolumeState,
+            SizeLabel:  size,
+        },
+        instanceID:  instanceID,
+        availableAt: now.Add(s.opts.TransitionDelay),
+    }
+    s.volumes[created.VolumeID] = created
+    s.advance(now)
+    return created
+}
+
+func (s *state) createVolume(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeCreateRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.DefaultRequestParams == nil || request.SizeInGB < 1 {
+        return nil, badRequest("region, tenantName and sizeInGB are required")
+    }
+    created := s.addVolume(request.TenantName, request.Region, request.VolumeName, request.SizeInGB, "", now)
+    return []service.Volume{created.Volume}, nil
+}
+
+func (s *state) createAndAttachVolume(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeCreateAndAttachRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.DefaultRequestParams == nil || request.SizeInGB < 1 {
+        return nil, badRequest("region, tenantName and sizeInGB are required")
+    }
+    i, ok := s.instances[request.InstanceId]
+    if !ok {
+        return nil, notFound("instance '%s' is not found", request.InstanceId)
+    }
+    created := s.addVolume(request.TenantName, request.Region, request.VolumeName, request.SizeInGB, i.InstanceID, now)
+    i.VolumesIds = append(i.VolumesIds, created.VolumeID)
+    return []service.Volume{created.Volume}, nil
+}
+
+func (s *state) deleteVolume(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeDeleteRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    v, ok := s.volumes[request.VolumeID]
+    if !ok {
+        return nil, notFound("No unique volume found by volume ID %s", request.VolumeID)
+    }
+    if v.System {
+        return nil, badRequest("system volume %s can not be removed", v.VolumeID)
+    }
+    if v.instanceID != "" {
+        return nil, badRequest("volume %s is attached to instance %s, it must be detached before removal", v.VolumeID, v.instanceID)
+    }
+    delete(s.volumes, v.VolumeID)
+    return nil, nil
+}
+
+func (s *state) detachVolume(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeDetachRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    v, ok := s.volumes[request.VolumeID]
+    if !ok {
+        return nil, notFound("No unique volume found by volume ID %s", request.VolumeID)
+    }
+    if v.System {
+        return nil, badRequest("system volume %s can not be detached", v.VolumeID)
+    }
+    if v.instanceID == "" || v.instanceID != request.InstanceId {
+        return nil, badRequest("volume %s is not attached to instance %s", v.VolumeID, request.InstanceId)
+    }
+    if i, ok := s.instances[v.instanceID]; ok {
+        i.VolumesIds = remove(i.VolumesIds, v.VolumeID)
+    }
+    v.instanceID = ""
+    v.State = service.DetachingVolumeState
+    v.availableAt = now.Add(s.opts.TransitionDelay)
+    return nil, nil
+}
+
+func (s *state) resizeVolume(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeResizeRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    v, ok := s.volumes[request.VolumeID]
+    if !ok {
+        return nil, notFound("No unique volume found by volume ID %s", request.VolumeID)
+    }
+    if request.SizeInGB < v.SizeLabel {
+        return nil, badRequest("size of volume %s can not be decreased from %d to %d GB", v.VolumeID, v.SizeLabel, request.SizeInGB)
+    }
+    v.SizeLabel = request.SizeInGB
+    v.State = service.ModifyingVolumeState
+    v.availableAt = now.Add(s.opts.TransitionDelay)
+    return nil, nil
+}
+
+func (s *state) describeVolumes(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.VolumeDescribeRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    volumes := make([]service.Volume, 0, len(request.VolumeIds))
+    for _, v := range s.volumes {
+        if len(request.VolumeIds) > 0 && !contains(request.VolumeIds, v.VolumeID) {
+            continue
+        }
+        if request.InstanceId != "" && v.instanceID != request.InstanceId {
+            continue
+        }
+        volumes = append(volumes, v.Volume)
+    }
+    sort.Slice(volumes, func(a, b int) bool { return volumes[a].VolumeID < volumes[b].VolumeID })
+    return volumes, nil
+}
+
+func (s *state) createScript(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.ScriptCreateRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.DefaultRequestParams == nil || request.FileName == "" {
+        return nil, badRequest("tenantName and fileName are required")
+    }
+    if _, ok := s.scripts[request.FileName]; ok {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: "script '" + request.FileName + "' already exists"}
+    }
+    script := &service.Script{
+        TenantName: request.TenantName,
+        Region:     request.Region,
+        Alias:      request.FileName,
+        FileName:   request.FileName,
+        Content:    request.ScriptContent,
+        Owner:      request.Email,
+    }
+    s.scripts[script.FileName] = script
+    return script, nil
+}
+
+func (s *state) deleteScript(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.ScriptDeleteRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    for _, name := range request.FileName {
+        if _, ok := s.scripts[name]; !ok {
+            return nil, notFound("script '%s' is not found", name)
+        }
+    }
+    for _, name := range request.FileName {
+        delete(s.scripts, name)
+    }
+    return nil, nil
+}
+
+func (s *state) describeScripts(body []byte, now time.Time) (interface{}, *apiError) {
+    scripts := make([]service.Script, 0, len(s.scripts))
+    for _, script := range s.scripts {
+        scripts = append(scripts, *script)
+    }
+    sort.Slice(scripts, func(a, b int) bool { return scripts[a].FileName < scripts[b].FileName })
+    return scripts, nil
+}
+
+func (s *state) createSchedule(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &struct {
+        Schedule *service.RequestSchedule `json:"schedule"`
+    }{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.Schedule == nil || request.Schedule.Name == "" || request.Schedule.Cron == "" {
+        return nil, badRequest("schedule displayName and cron are required")
+    }
+    key := strings.ToLower(request.Schedule.Name)
+    if _, ok := s.schedules[key]; ok {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: "schedule '" + request.Schedule.Name + "' already exists"}
+    }
+    for _, scheduled := range request.Schedule.Instances {
+        if _, ok := s.instances[scheduled.InstanceId]; !ok {
+            return nil, notFound("instance '%s' is not found", scheduled.InstanceId)
+        }
+    }
+    schedule := &service.Schedule{
+        DefaultRequestParams: request.Schedule.DefaultRequestParams,
+        Name:                 request.Schedule.Name,
+        ScheduleName:         key,
+        ScheduleOwner:        s.opts.UserIdentifier,
+        Cron:                 request.Schedule.Cron,
+        Description:          request.Schedule.Description,
+        Action:               request.Schedule.Action,
+        Type:                 request.Schedule.Type,
+        Cloud:                request.Schedule.Cloud,
+        Instances:            request.Schedule.Instances,
+    }
+    s.schedules[key] = schedule
+    return schedule, nil
+}
+
+func (s *state) deleteSchedule(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.RequestSchedule{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    key := strings.ToLower(request.ScheduleName)
+    if key == "" {
+        key = strings.ToLower(request.Name)
+    }
+    if _, ok := s.schedules[key]; !ok {
+        return nil, notFound("schedule '%s' is not found", key)
+    }
+    delete(s.schedules, key)
+    return nil, nil
+}
+
+func (s *state) describeSchedules(body []byte, now time.Time) (interface{}, *apiError) {
+    schedules := make([]service.Schedule, 0, len(s.schedules))
+    for _, schedule := range s.schedules {
+        schedules = append(schedules, *schedule)
+    }
+    sort.Slice(schedules, func(a, b int) bool { return schedules[a].ScheduleName < schedules[b].ScheduleName })
+    return schedules, nil
+}
+
+func (s *state) createKeypair(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.KeypairRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if request.Name == "" || request.KeypairContent == nil || request.Content == "" {
+        return nil, badRequest("name and publicKey are required")
+    }
+    if _, ok := s.keypairs[request.Name]; ok {
+        return nil, &apiError{StatusCode: http.StatusConflict, Message: "key '" + request.Name + "' already exists"}
+    }
+    keypair := &service.Keypair{
+        Name:        request.Name,
+        PublicPart:  request.Content,
+        Email:       request.Email,
+        Fingerprint: fmt.Sprintf("%x", len(request.Content)),
+    }
+    if request.KeypairTenantName != nil {
+        keypair.TenantName = request.TenantName
+    }
+    if request.KeypairCloud != nil {
+        keypair.Cloud = request.Cloud
+    }
+    if request.KeypairAllTenants != nil {
+        keypair.AllTenants = request.AllTenants
+    }
+    s.keypairs[keypair.Name] = keypair
+    return keypair, nil
+}
+
+func (s *state) deleteKeypair(body []byte, now time.Time) (interface{}, *apiError) {
+    request := &service.KeypairRequest{}
+    if err := decode(body, request); err != nil {
+        return nil, err
+    }
+    if _, ok := s.keypairs[request.Name]; !ok {
+        return nil, notFound("key '%s' is not found", request.Name)
+    }
+    delete(s.keypairs, request.Name)
+    return nil, nil
+}
+
+func (s *state) describeKeypairs(body []byte, now time.Time) (interface{}, *apiError) {
+    keypairs := make([]service.Keypair, 0, len(s.keypairs))
+    for _, keypair := range s.keypairs {
+        keypairs = append(keypairs, *keypair)
+    }
+    sort.Slice(keypairs, func(a, b int) bool { return keypairs[a].Name < keypairs[b].Name })
+    return keypairs, nil
+}
+
+func (s *state) chefProfiles(body []byte, now time.Time) (interface{}, *apiError) {
+    return s.opts.ChefProfiles, nil
+}
+
+func sortTags(tags []service.Tag) {
+    sort.Slice(tags, func(a, b int) bool { return tags[a].Key < tags[b].Key })
+}
+
+func contains(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
+
+func remove(values []string, value string) []string {
+    result := make([]string, 0, len(values))
+    for _, v := range values {
+        if v != value {
+            result = append(result, v)
+        }
+    }
+    return result
+}